@@ -0,0 +1,265 @@
+package contenttype
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// formField returns the form name of the struct field and whether it is omitted when empty.
+// An empty name means the field is skipped.
+func formField(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	tag := field.Tag.Get("form")
+	if tag == "-" {
+		return "", false
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, strings.Contains(options, "omitempty")
+}
+
+// isEmbeddedForm returns whether the field is an untagged embedded struct, whose fields are bound as the ones of
+// the embedding struct.
+func isEmbeddedForm(field reflect.StructField) bool {
+	return field.Anonymous && field.IsExported() && field.Type.Kind() == reflect.Struct && field.Tag.Get("form") == ""
+}
+
+// formKey joins the prefix and the name with the dot notation.
+func formKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
+
+// encodeForm adds the fields of the given struct to the form values.
+func encodeForm(values url.Values, prefix string, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if isEmbeddedForm(field) {
+			if err := encodeForm(values, prefix, v.Field(i)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		name, omitempty := formField(field)
+		if name == "" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if omitempty && fv.IsZero() {
+			continue
+		}
+
+		if err := encodeFormValue(values, formKey(prefix, name), fv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeFormValue adds the given value to the form values under the key.
+func encodeFormValue(values url.Values, key string, v reflect.Value) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	if isScalar(v.Type()) {
+		s, err := formatScalar(Form, v)
+		if err != nil {
+			return fmt.Errorf("form field %s: %w", key, err)
+		}
+
+		values.Add(key, s)
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return encodeForm(values, key, v)
+	case reflect.Slice, reflect.Array:
+		scalar := isScalar(indirectType(v.Type().Elem()))
+		for i := 0; i < v.Len(); i++ {
+			k := key
+			if !scalar {
+				k = fmt.Sprintf("%s[%d]", key, i)
+			}
+
+			if err := encodeFormValue(values, k, v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("form field %s: %w", key, NewUnsupportedTypeError(Form, v.Interface()))
+}
+
+// normalizeForm rewrites the bracket notation of the keys to the dot notation, e.g. `items[0][name]` to `items.0.name`
+// and `tags[]` to `tags`.
+func normalizeForm(values url.Values) url.Values {
+	normalized := make(url.Values, len(values))
+	for key, value := range values {
+		if strings.Contains(key, "[") {
+			key = strings.TrimSuffix(key, "[]")
+			key = strings.NewReplacer("][", ".", "[", ".", "]", "").Replace(key)
+		}
+
+		normalized[key] = append(normalized[key], value...)
+	}
+
+	return normalized
+}
+
+// decodeForm stores the form values in the fields of the given struct.
+func decodeForm(values url.Values, prefix string, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if isEmbeddedForm(field) {
+			if err := decodeForm(values, prefix, v.Field(i)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		name, _ := formField(field)
+		if name == "" {
+			continue
+		}
+
+		if err := decodeFormValue(values, formKey(prefix, name), v.Field(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeFormValue stores the form values found under the key in the given value.
+// A nil pointer is only allocated when a value exists for it.
+func decodeFormValue(values url.Values, key string, v reflect.Value) error {
+	if !hasFormKey(values, key) {
+		return nil
+	}
+
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		v = v.Elem()
+	}
+
+	if isScalar(v.Type()) {
+		if value := values[key]; len(value) > 0 {
+			if err := parseScalar(Form, v, value[0]); err != nil {
+				return fmt.Errorf("form field %s: %w", key, err)
+			}
+		}
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return decodeForm(values, key, v)
+	case reflect.Slice:
+		if isScalar(indirectType(v.Type().Elem())) {
+			value := values[key]
+			slice := reflect.MakeSlice(v.Type(), len(value), len(value))
+			for i, s := range value {
+				elem := slice.Index(i)
+				for elem.Kind() == reflect.Pointer {
+					elem.Set(reflect.New(elem.Type().Elem()))
+					elem = elem.Elem()
+				}
+
+				if err := parseScalar(Form, elem, s); err != nil {
+					return fmt.Errorf("form field %s: %w", key, err)
+				}
+			}
+
+			v.Set(slice)
+
+			return nil
+		}
+
+		indexes := formIndexes(values, key)
+		slice := reflect.MakeSlice(v.Type(), len(indexes), len(indexes))
+		for i, index := range indexes {
+			if err := decodeFormValue(values, formKey(key, strconv.Itoa(index)), slice.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		v.Set(slice)
+
+		return nil
+	}
+
+	return fmt.Errorf("form field %s: %w", key, NewUnsupportedTypeError(Form, v.Interface()))
+}
+
+// hasFormKey returns whether the form has a value for the key or for a key nested under it.
+func hasFormKey(values url.Values, key string) bool {
+	if _, ok := values[key]; ok {
+		return true
+	}
+
+	for k := range values {
+		if strings.HasPrefix(k, key+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formIndexes returns the sorted slice indexes nested under the key, e.g. 0 and 2 for `items.0.name` and `items.2.name`.
+func formIndexes(values url.Values, key string) []int {
+	seen := make(map[int]struct{})
+	for k := range values {
+		if !strings.HasPrefix(k, key+".") {
+			continue
+		}
+
+		index, _, _ := strings.Cut(strings.TrimPrefix(k, key+"."), ".")
+		if i, err := strconv.Atoi(index); err == nil && i >= 0 {
+			seen[i] = struct{}{}
+		}
+	}
+
+	indexes := make([]int, 0, len(seen))
+	for i := range seen {
+		indexes = append(indexes, i)
+	}
+
+	sort.Ints(indexes)
+
+	return indexes
+}
@@ -0,0 +1,29 @@
+package contenttype
+
+import (
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+var (
+	// cborEncMode is the core deterministic encoding mode of RFC 8949.
+	cborEncMode, _ = cbor.CoreDetEncOptions().EncMode()
+)
+
+// Marshal returns a reader for the given value, encoded as the reader is consumed. An encoding error is returned
+// before the first byte is written, a later one by the reader. The reader is an io.ReadCloser, to be read until EOF
+// or closed to stop the encoding, and the value must not be mutated until then.
+func (ct *CborType) Marshal(value any) (io.Reader, error) {
+	return startMarshal(ct.Encode, value)
+}
+
+// Encode writes the encoding of the given value to the writer.
+func (ct *CborType) Encode(w io.Writer, value any) error {
+	return cborEncMode.NewEncoder(w).Encode(value)
+}
+
+// Unmarshal parses the given reader and stores the result in the value pointed to by value.
+func (ct *CborType) Unmarshal(r io.Reader, value any) error {
+	return cbor.NewDecoder(r).Decode(value)
+}
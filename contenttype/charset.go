@@ -0,0 +1,66 @@
+package contenttype
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/leliuga/data/constants"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+var (
+	// ErrCharsetInvalid is returned when a charset is unknown.
+	ErrCharsetInvalid = errors.New("invalid charset")
+)
+
+// NewCharsetReader returns a reader transcoding the given reader from the charset to UTF-8, e.g. ISO-8859-1.
+// The reader is returned as is for UTF-8 or an empty charset.
+func NewCharsetReader(charset string, r io.Reader) (io.Reader, error) {
+	enc, err := charsetEncoding(charset)
+	if err != nil || enc == nil {
+		return r, err
+	}
+
+	return enc.NewDecoder().Reader(r), nil
+}
+
+// NewCharsetWriter returns a writer transcoding UTF-8 to the charset before writing to the given writer.
+// The writer is returned as is for UTF-8 or an empty charset.
+func NewCharsetWriter(charset string, w io.Writer) (io.Writer, error) {
+	enc, err := charsetEncoding(charset)
+	if err != nil || enc == nil {
+		return w, err
+	}
+
+	return enc.NewEncoder().Writer(w), nil
+}
+
+// IsUTF8 returns whether the charset is UTF-8, an empty charset defaults to constants.DefaultCharset.
+func IsUTF8(charset string) bool {
+	if charset == "" {
+		charset = constants.DefaultCharset
+	}
+
+	return strings.EqualFold(charset, "utf-8") || strings.EqualFold(charset, "utf8")
+}
+
+// charsetEncoding returns the encoding of the charset, nil for UTF-8.
+func charsetEncoding(charset string) (encoding.Encoding, error) {
+	if IsUTF8(charset) {
+		return nil, nil
+	}
+
+	enc, err := ianaindex.IANA.Encoding(charset)
+	if err != nil || enc == nil {
+		return nil, ErrCharsetInvalid
+	}
+
+	if enc == unicode.UTF8 {
+		return nil, nil
+	}
+
+	return enc, nil
+}
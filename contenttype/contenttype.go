@@ -3,7 +3,6 @@ package contenttype
 import (
 	"bytes"
 	"errors"
-	"strings"
 )
 
 const (
@@ -14,27 +13,54 @@ const (
 	MsgPack
 	Text
 	Yaml
+	Multipart
+	TextXml
+	Xml
+	Csv
+	Tsv
+	Ndjson
+	Protobuf
+	Cbor
+	EventStream
 )
 
 var (
 	// Names is a map of content type names to content type values.
 	Names = map[ContentType]string{
-		Form:    "application/x-www-form-urlencoded",
-		Html:    "text/html",
-		Json:    "application/json",
-		MsgPack: "application/msgpack",
-		Text:    "text/plain",
-		Yaml:    "application/yaml",
+		Cbor:        "application/cbor",
+		Csv:         "text/csv",
+		EventStream: "text/event-stream",
+		Form:        "application/x-www-form-urlencoded",
+		Html:        "text/html",
+		Json:        "application/json",
+		MsgPack:     "application/msgpack",
+		Multipart:   "multipart/form-data",
+		Ndjson:      "application/x-ndjson",
+		Protobuf:    "application/x-protobuf",
+		Text:        "text/plain",
+		TextXml:     "text/xml",
+		Tsv:         "text/tab-separated-values",
+		Xml:         "application/xml",
+		Yaml:        "application/yaml",
 	}
 
 	// Set is a map of content type values to content type marshal and unmarshal.
 	Set = map[ContentType]IContentType{
-		Form:    &FormType{},
-		Html:    &HtmlType{},
-		Json:    &JsonType{},
-		MsgPack: &MsgPackType{},
-		Text:    &TextType{},
-		Yaml:    &YamlType{},
+		Cbor:        &CborType{},
+		Csv:         &CsvType{Comma: ','},
+		EventStream: &EventStreamType{},
+		Form:        &FormType{},
+		Html:        &HtmlType{},
+		Json:        &JsonType{},
+		MsgPack:     &MsgPackType{},
+		Multipart:   &MultipartType{},
+		Ndjson:      &NdjsonType{},
+		Protobuf:    &ProtobufType{},
+		Text:        &TextType{},
+		TextXml:     &XmlType{},
+		Tsv:         &CsvType{Comma: '\t'},
+		Xml:         &XmlType{},
+		Yaml:        &YamlType{},
 	}
 
 	// ErrInvalid is returned when the content type is invalid.
@@ -58,15 +84,14 @@ func (ct *ContentType) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-// Parse parses content type string.
+// Parse parses content type string, e.g. "text/html; charset=utf-8", ignoring its parameters.
 func Parse(name string) ContentType {
-	for k, v := range Names {
-		if strings.HasPrefix(name, v) {
-			return k
-		}
+	mt, err := ParseMediaType(name)
+	if err != nil {
+		return Invalid
 	}
 
-	return Invalid
+	return mt.ContentType()
 }
 
 // MustParse parses content type string or panics.
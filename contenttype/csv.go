@@ -0,0 +1,398 @@
+package contenttype
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leliuga/data"
+	"github.com/leliuga/data/constants"
+)
+
+type (
+	// csvField defines a struct field bound to a csv column.
+	csvField struct {
+		name  string
+		index []int
+	}
+)
+
+// Marshal returns a reader for the given value.
+func (ct *CsvType) Marshal(value any) (io.Reader, error) {
+	buffer := bytes.NewBuffer(nil)
+	if err := ct.Encode(buffer, value); err != nil {
+		return nil, err
+	}
+
+	return buffer, nil
+}
+
+// Unmarshal parses the given reader and stores the result in the value pointed to by value.
+func (ct *CsvType) Unmarshal(r io.Reader, value any) error {
+	return ct.Decode(r, value)
+}
+
+// Encode writes the given rows to the writer, a header row first.
+// The value must be a [][]string, written as is, a []data.Map[any], a []map[string]any or a []map[string]string,
+// whose header is the sorted keys of all the rows, or a slice of structs, whose header is the `csv` tag, or the name,
+// of the fields. A "-" tag skips the field, time.Time is written with constants.DefaultDateTimeFormat.
+func (ct *CsvType) Encode(w io.Writer, value any) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = ct.comma()
+
+	var err error
+	switch t := value.(type) {
+	case [][]string:
+		err = writer.WriteAll(t)
+	case []data.Map[any]:
+		rows := make([]map[string]any, len(t))
+		for i, row := range t {
+			rows[i] = row
+		}
+
+		err = ct.encodeMaps(writer, rows)
+	case []map[string]any:
+		err = ct.encodeMaps(writer, t)
+	case []map[string]string:
+		rows := make([]map[string]any, len(t))
+		for i, row := range t {
+			rows[i] = make(map[string]any, len(row))
+			for key, value := range row {
+				rows[i][key] = value
+			}
+		}
+
+		err = ct.encodeMaps(writer, rows)
+	default:
+		err = ct.encodeStructs(writer, value)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// Decode reads the header row and the rows from the reader and stores them in the value pointed to by value.
+// The value must be a *[][]string, read as is, a *[]data.Map[any] or a *[]map[string]any, whose values are coerced to
+// the data.Kind detected from the text, a *[]map[string]string or a pointer to a slice of structs, whose fields are
+// bound as described by Encode. Empty cells leave the fields untouched.
+func (ct *CsvType) Decode(r io.Reader, value any) error {
+	reader := csv.NewReader(r)
+	reader.Comma = ct.comma()
+
+	if t, ok := value.(*[][]string); ok {
+		records, err := reader.ReadAll()
+		if err != nil {
+			return err
+		}
+
+		*t = records
+
+		return nil
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		header, err = nil, nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	switch t := value.(type) {
+	case *[]data.Map[any]:
+		*t = (*t)[:0]
+		return decodeCsvRows(reader, func(record []string) error {
+			*t = append(*t, csvMap(header, record, coerceCsvValue))
+			return nil
+		})
+	case *[]map[string]any:
+		*t = (*t)[:0]
+		return decodeCsvRows(reader, func(record []string) error {
+			*t = append(*t, csvMap(header, record, coerceCsvValue))
+			return nil
+		})
+	case *[]map[string]string:
+		*t = (*t)[:0]
+		return decodeCsvRows(reader, func(record []string) error {
+			*t = append(*t, csvMap(header, record, func(s string) string { return s }))
+			return nil
+		})
+	}
+
+	return ct.decodeStructs(reader, header, value)
+}
+
+// encodeMaps writes the maps with the sorted keys of all the rows as header.
+func (ct *CsvType) encodeMaps(writer *csv.Writer, rows []map[string]any) error {
+	keys := make(map[string]struct{})
+	for _, row := range rows {
+		for key := range row {
+			keys[key] = struct{}{}
+		}
+	}
+
+	header := sortedKeys(keys)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(header))
+	for _, row := range rows {
+		for i, key := range header {
+			cell, err := ct.formatCell(reflect.ValueOf(row[key]))
+			if err != nil {
+				return fmt.Errorf("csv column %s: %w", key, err)
+			}
+
+			record[i] = cell
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeStructs writes the slice of structs with the fields as header.
+func (ct *CsvType) encodeStructs(writer *csv.Writer, value any) error {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return NewUnsupportedTypeError(ct.contentType(), value)
+	}
+
+	elemType := indirectType(rv.Type().Elem())
+	if elemType.Kind() != reflect.Struct {
+		return NewUnsupportedTypeError(ct.contentType(), value)
+	}
+
+	fields := csvFields(elemType, nil)
+	header := make([]string, len(fields))
+	for i, field := range fields {
+		header[i] = field.name
+	}
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(fields))
+	for i := 0; i < rv.Len(); i++ {
+		elem := reflect.Indirect(rv.Index(i))
+		if !elem.IsValid() {
+			continue
+		}
+
+		for j, field := range fields {
+			cell, err := ct.formatCell(elem.FieldByIndex(field.index))
+			if err != nil {
+				return fmt.Errorf("csv column %s: %w", field.name, err)
+			}
+
+			record[j] = cell
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeStructs reads the rows into the slice of structs pointed to by value.
+func (ct *CsvType) decodeStructs(reader *csv.Reader, header []string, value any) error {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Slice ||
+		indirectType(rv.Elem().Type().Elem()).Kind() != reflect.Struct {
+		return NewUnsupportedTypeError(ct.contentType(), value)
+	}
+
+	slice := rv.Elem()
+	slice.SetLen(0)
+	elemType := slice.Type().Elem()
+
+	columns := make(map[string][]int)
+	for _, field := range csvFields(indirectType(elemType), nil) {
+		columns[field.name] = field.index
+	}
+
+	return decodeCsvRows(reader, func(record []string) error {
+		elem := reflect.New(indirectType(elemType)).Elem()
+		for i, cell := range record {
+			index, ok := columns[header[i]]
+			if !ok || cell == "" {
+				continue
+			}
+
+			v := elem.FieldByIndex(index)
+			for v.Kind() == reflect.Pointer {
+				v.Set(reflect.New(v.Type().Elem()))
+				v = v.Elem()
+			}
+
+			if err := parseScalar(ct.contentType(), v, cell); err != nil {
+				line, _ := reader.FieldPos(i)
+				return fmt.Errorf("csv line %d column %s: %w", line, header[i], err)
+			}
+		}
+
+		for elem.Type() != elemType {
+			elem = elem.Addr()
+		}
+
+		slice.Set(reflect.Append(slice, elem))
+
+		return nil
+	})
+}
+
+// decodeCsvRows calls fn for each remaining row of the reader.
+func decodeCsvRows(reader *csv.Reader, fn func(record []string) error) error {
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err = fn(record); err != nil {
+			return err
+		}
+	}
+}
+
+// formatCell formats the given value as a csv cell, nil as an empty cell.
+func (ct *CsvType) formatCell(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return "", nil
+	}
+
+	if !isScalar(v.Type()) {
+		return "", NewUnsupportedTypeError(ct.contentType(), v.Interface())
+	}
+
+	return formatScalar(ct.contentType(), v)
+}
+
+// comma returns the field delimiter.
+func (ct *CsvType) comma() rune {
+	if ct.Comma == 0 {
+		return ','
+	}
+
+	return ct.Comma
+}
+
+// contentType returns the content type matching the field delimiter.
+func (ct *CsvType) contentType() ContentType {
+	if ct.comma() == '\t' {
+		return Tsv
+	}
+
+	return Csv
+}
+
+// csvFields returns the fields of the struct bound to csv columns, the fields of embedded structs included.
+func csvFields(t reflect.Type, index []int) (fields []csvField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		if field.Anonymous && field.IsExported() && field.Type.Kind() == reflect.Struct && field.Tag.Get("csv") == "" {
+			fields = append(fields, csvFields(field.Type, fieldIndex)...)
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("csv"), ",")
+		if !field.IsExported() || name == "-" || !isScalar(indirectType(field.Type)) {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, csvField{name: name, index: fieldIndex})
+	}
+
+	return fields
+}
+
+// csvMap returns the record as a map keyed by the header.
+func csvMap[T any](header, record []string, convert func(string) T) map[string]T {
+	m := make(map[string]T, len(header))
+	for i, cell := range record {
+		if i < len(header) {
+			m[header[i]] = convert(cell)
+		}
+	}
+
+	return m
+}
+
+// coerceCsvValue converts the cell to the Go value of the data.Kind detected from its text: int, int64 for the values
+// out of the int32 range, float64, bool, time.Time for dates and times, or string.
+func coerceCsvValue(cell string) any {
+	v := strings.ToLower(strings.TrimSpace(cell))
+
+	switch data.DetectValueKind(v, true) {
+	case data.KindInt8, data.KindInt16, data.KindInt32:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	case data.KindInt64:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case data.KindFloat32, data.KindFloat64:
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	case data.KindBoolean:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	case data.KindDateTime:
+		if t, err := time.Parse(constants.DefaultDateTimeFormat, v); err == nil {
+			return t
+		}
+	case data.KindDate:
+		if t, err := time.Parse(constants.DefaultDateFormat, v); err == nil {
+			return t
+		}
+	case data.KindTime:
+		if t, err := time.Parse(constants.DefaultTimeFormat, v); err == nil {
+			return t
+		}
+	}
+
+	return cell
+}
@@ -0,0 +1,155 @@
+package contenttype_test
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leliuga/data"
+	"github.com/leliuga/data/contenttype"
+)
+
+type CsvBase struct {
+	ID int `csv:"id"`
+}
+
+type CsvRow struct {
+	CsvBase
+	Name    string    `csv:"name"`
+	Score   *float64  `csv:"score"`
+	Active  bool      // bound by its name
+	Created time.Time `csv:"created"`
+	Secret  string    `csv:"-"`
+}
+
+func marshalCsv(t *testing.T, ct *contenttype.CsvType, value any) string {
+	t.Helper()
+
+	r, err := ct.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	return string(b)
+}
+
+func TestCsvStructs(t *testing.T) {
+	score := 9.5
+	created := time.Date(2023, 1, 25, 10, 10, 10, 0, time.UTC)
+	in := []CsvRow{
+		{CsvBase: CsvBase{ID: 1}, Name: "Ada, Countess", Score: &score, Active: true, Created: created, Secret: "x"},
+		{CsvBase: CsvBase{ID: 2}, Name: "Alan"},
+	}
+
+	tests := []struct {
+		name string
+		ct   *contenttype.CsvType
+		want string
+	}{
+		{"csv", &contenttype.CsvType{}, "id,name,score,Active,created\n" +
+			"1,\"Ada, Countess\",9.5,true,2023-01-25 10:10:10\n" +
+			"2,Alan,,false,0001-01-01 00:00:00\n"},
+		{"tsv", &contenttype.CsvType{Comma: '\t'}, "id\tname\tscore\tActive\tcreated\n" +
+			"1\tAda, Countess\t9.5\ttrue\t2023-01-25 10:10:10\n" +
+			"2\tAlan\t\tfalse\t0001-01-01 00:00:00\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := marshalCsv(t, tt.ct, in)
+			if body != tt.want {
+				t.Fatalf("Marshal() = %q, want %q", body, tt.want)
+			}
+
+			var out []CsvRow
+			if err := tt.ct.Unmarshal(strings.NewReader(body), &out); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			want := append([]CsvRow(nil), in...)
+			want[0].Secret = ""
+			if !reflect.DeepEqual(out, want) {
+				t.Errorf("Unmarshal() = %+v, want %+v", out, want)
+			}
+		})
+	}
+}
+
+func TestCsvHeaderMapping(t *testing.T) {
+	// the columns are bound by the header, whatever their order, after a byte order mark, unknown columns being ignored
+	body := "\ufeffname,unknown,id,score\nAda,?,1,\nAlan,?,2,3\n"
+
+	var out []*CsvRow
+	if err := (&contenttype.CsvType{}).Unmarshal(strings.NewReader(body), &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if len(out) != 2 {
+		t.Fatalf("Unmarshal() = %d rows, want 2", len(out))
+	}
+	if out[0].ID != 1 || out[0].Name != "Ada" || out[0].Score != nil {
+		t.Errorf("Unmarshal()[0] = %+v, want ID 1, Name Ada and no score", out[0])
+	}
+	if out[1].ID != 2 || out[1].Name != "Alan" || out[1].Score == nil || *out[1].Score != 3 {
+		t.Errorf("Unmarshal()[1] = %+v, want ID 2, Name Alan and score 3", out[1])
+	}
+
+	if err := (&contenttype.CsvType{}).Unmarshal(strings.NewReader("id\nx\n"), &out); err == nil {
+		t.Errorf("Unmarshal() error = nil, want an error for an invalid integer")
+	}
+}
+
+func TestCsvMaps(t *testing.T) {
+	body := "bool,date,datetime,float,int,negative,text\n" +
+		"true,2023-01-25,2023-01-25 10:10:10,1.5,42,-7,Hello\n"
+
+	var out []data.Map[any]
+	if err := (&contenttype.CsvType{}).Unmarshal(strings.NewReader(body), &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := []data.Map[any]{{
+		"bool":     true,
+		"date":     time.Date(2023, 1, 25, 0, 0, 0, 0, time.UTC),
+		"datetime": time.Date(2023, 1, 25, 10, 10, 10, 0, time.UTC),
+		"float":    1.5,
+		"int":      42,
+		"negative": -7,
+		"text":     "Hello",
+	}}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("Unmarshal() = %v, want %v", out, want)
+	}
+
+	// time.Time is written with constants.DefaultDateTimeFormat
+	encoded := strings.Replace(body, "true,2023-01-25,", "true,2023-01-25 00:00:00,", 1)
+	if got := marshalCsv(t, &contenttype.CsvType{}, want); got != encoded {
+		t.Errorf("Marshal() = %q, want %q", got, encoded)
+	}
+
+	var text []map[string]string
+	if err := (&contenttype.CsvType{}).Unmarshal(strings.NewReader(body), &text); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if text[0]["int"] != "42" || text[0]["bool"] != "true" {
+		t.Errorf("Unmarshal() = %v, want the cells as is", text)
+	}
+}
+
+func TestCsvUnsupported(t *testing.T) {
+	if _, err := (&contenttype.CsvType{}).Marshal([]int{1}); err == nil {
+		t.Errorf("Marshal() error = nil, want an error for a slice of integers")
+	}
+
+	var out []int
+	if err := (&contenttype.CsvType{}).Unmarshal(strings.NewReader("a\n1\n"), &out); err == nil {
+		t.Errorf("Unmarshal() error = nil, want an error for a slice of integers")
+	}
+}
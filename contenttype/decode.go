@@ -0,0 +1,197 @@
+package contenttype
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type (
+	// limitedReader is a reader failing with ErrContentTooLarge when more than remaining bytes are read.
+	limitedReader struct {
+		r    io.Reader
+		max  int64
+		read int64
+		err  error // kept as decoders may not return the errors of the reader as is
+	}
+)
+
+var (
+	// ErrContentTooLarge is returned when a body exceeds the maximum size of a codec.
+	ErrContentTooLarge = errors.New("content too large")
+
+	// ErrMaxDepth is returned when a body exceeds the maximum nesting depth of a codec.
+	ErrMaxDepth = errors.New("maximum nesting depth exceeded")
+
+	// unknownFieldPattern matches the unknown field errors of the json and msgpack decoders.
+	unknownFieldPattern = regexp.MustCompile(`unknown field ("(?:[^"\\]|\\.)*")`)
+)
+
+// Error returns the error message.
+func (e *DecodeError) Error() string {
+	var b strings.Builder
+	b.WriteString("decoding " + e.ContentType.String())
+	if e.Path != "" {
+		b.WriteString(" at " + e.Path)
+	} else if e.Offset > -1 {
+		b.WriteString(" at offset " + strconv.FormatInt(e.Offset, 10))
+	}
+
+	return b.String() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// newDecodeError returns the error as a DecodeError of the content type, as is if it is already one.
+func newDecodeError(ct ContentType, err error) *DecodeError {
+	var de *DecodeError
+	if errors.As(err, &de) {
+		return de
+	}
+
+	return &DecodeError{ContentType: ct, Offset: -1, Err: err}
+}
+
+// newLimitedReader creates a new limitedReader failing with ErrContentTooLarge when more than max bytes are read,
+// 0 meaning no limit.
+func newLimitedReader(r io.Reader, max int64) *limitedReader {
+	return &limitedReader{r: r, max: max}
+}
+
+// Read reads up to len(p) bytes into p.
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if lr.max <= 0 {
+		return lr.r.Read(p)
+	}
+
+	if lr.err != nil {
+		return 0, lr.err
+	}
+
+	if remaining := lr.max - lr.read; int64(len(p)) > remaining+1 {
+		p = p[:remaining+1]
+	}
+
+	n, err := lr.r.Read(p)
+	if lr.read += int64(n); lr.read > lr.max {
+		lr.err = ErrContentTooLarge
+		return 0, lr.err
+	}
+
+	return n, err
+}
+
+// unknownField returns the key of an unknown field error of the json and msgpack decoders.
+func unknownField(err error) (string, bool) {
+	match := unknownFieldPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return "", false
+	}
+
+	key, err := strconv.Unquote(match[1])
+
+	return key, err == nil
+}
+
+// locateUnknownField returns the path of the first key equal to the given key in the decoded tree of maps and
+// slices not matching a field of the struct type bound to it, field names being taken from the given tag.
+func locateUnknownField(tree any, t reflect.Type, tag, key, path string) (string, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch node := tree.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := structFields(t, tag)
+			for _, k := range sortedKeys(node) {
+				field, ok := fields[fieldKey(tag, k)]
+				if !ok {
+					if k == key {
+						return path + "." + k, true
+					}
+					continue
+				}
+
+				if p, ok := locateUnknownField(node[k], field, tag, key, path+"."+k); ok {
+					return p, true
+				}
+			}
+		case reflect.Map:
+			for _, k := range sortedKeys(node) {
+				if p, ok := locateUnknownField(node[k], t.Elem(), tag, key, path+"."+k); ok {
+					return p, true
+				}
+			}
+		}
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, v := range node {
+				if p, ok := locateUnknownField(v, t.Elem(), tag, key, fmt.Sprintf("%s[%d]", path, i)); ok {
+					return p, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// structFields returns the types of the fields of the struct type by key, see fieldKey, taken from the given tag
+// or the field name, including the fields promoted from embedded structs.
+func structFields(t reflect.Type, tag string) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				for k, v := range structFields(ft, tag) {
+					if _, ok := fields[k]; !ok {
+						fields[k] = v
+					}
+				}
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields[fieldKey(tag, name)] = field.Type
+	}
+
+	return fields
+}
+
+// fieldKey returns the key matching the field name for the decoder of the given tag. Only the json decoder matches
+// the field names case-insensitively.
+func fieldKey(tag, name string) string {
+	if tag == "json" {
+		return strings.ToLower(name)
+	}
+
+	return name
+}
@@ -0,0 +1,85 @@
+package contenttype_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/leliuga/data/contenttype"
+)
+
+type DecodeInner struct {
+	Name string `json:"name" msgpack:"name"`
+}
+
+type DecodeOuter struct {
+	Inner DecodeInner `json:"inner" msgpack:"inner"`
+}
+
+func TestDecodeUnknownFieldPath(t *testing.T) {
+	data, err := msgpack.Marshal(map[string]any{"inner": map[string]any{"NAME": "x"}})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	mt := &contenttype.MsgPackType{DisallowUnknownFields: true}
+	err = mt.Unmarshal(bytes.NewReader(data), &DecodeOuter{})
+
+	var de *contenttype.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("Unmarshal() error = %v, want a DecodeError", err)
+	}
+
+	if de.Path != "$.inner.NAME" {
+		t.Fatalf("Unmarshal() path = %q, want %q", de.Path, "$.inner.NAME")
+	}
+}
+
+type DecodeItem struct {
+	Name string `json:"name" yaml:"name"`
+	Tags []int  `json:"tags" yaml:"tags"`
+}
+
+type DecodeDocument struct {
+	Inner DecodeItem            `json:"inner" yaml:"inner"`
+	Items []DecodeItem          `json:"items" yaml:"items"`
+	Index map[string]DecodeItem `json:"index" yaml:"index"`
+	Count int                   `json:"count" yaml:"count"`
+}
+
+func TestDecodeTypeMismatchPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		codec contenttype.IContentType
+		body  string
+		want  string
+	}{
+		{"json top level", &contenttype.JsonType{}, `{"count":"x"}`, "$.count"},
+		{"json nested", &contenttype.JsonType{}, `{"inner":{"name":1}}`, "$.inner.name"},
+		{"json slice", &contenttype.JsonType{}, `{"items":[{"name":"a"},{"name":2}]}`, "$.items[1].name"},
+		{"json slice of slice", &contenttype.JsonType{}, `{"inner":{"tags":[1, "x"]}}`, "$.inner.tags[1]"},
+		{"json map", &contenttype.JsonType{}, `{"index": {"k": {"name": [3]}}}`, "$.index.k.name"},
+		{"json after values", &contenttype.JsonType{}, `{"items":[{"tags":[1,2]}],"inner":{"name":"a","tags":["x"]}}`, "$.inner.tags[0]"},
+		{"yaml top level", &contenttype.YamlType{}, "count: x\n", "$.count"},
+		{"yaml nested", &contenttype.YamlType{}, "inner:\n  name: [1]\n", "$.inner.name"},
+		{"yaml slice", &contenttype.YamlType{}, "items:\n  - name: a\n  - tags: [1, x]\n", "$.items[1].tags[1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.codec.Unmarshal(strings.NewReader(tt.body), &DecodeDocument{})
+
+			var de *contenttype.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("Unmarshal() error = %v, want a DecodeError", err)
+			}
+
+			if de.Path != tt.want {
+				t.Errorf("Unmarshal() path = %q, want %q (%v)", de.Path, tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package contenttype
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+	"github.com/leliuga/data/schema/http"
+)
+
+const (
+	EncodingInvalid Encoding = iota
+	EncodingIdentity
+	EncodingGzip
+	EncodingDeflate
+	EncodingBrotli
+	EncodingZstd
+)
+
+var (
+	// EncodingNames is a map of encoding values to encoding names.
+	EncodingNames = map[Encoding]string{
+		EncodingBrotli:   "br",
+		EncodingDeflate:  "deflate",
+		EncodingGzip:     "gzip",
+		EncodingIdentity: "identity",
+		EncodingZstd:     "zstd",
+	}
+
+	// Encodings is the list of supported encodings in order of preference for negotiation.
+	Encodings = []Encoding{
+		EncodingBrotli,
+		EncodingZstd,
+		EncodingGzip,
+		EncodingDeflate,
+		EncodingIdentity,
+	}
+
+	// ErrEncodingInvalid is returned when the encoding is invalid.
+	ErrEncodingInvalid = errors.New("invalid content encoding")
+)
+
+// String encoding to string
+func (e Encoding) String() string {
+	return EncodingNames[e]
+}
+
+// MarshalJSON encoding to json
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + e.String() + `"`), nil
+}
+
+// UnmarshalJSON encoding from json
+func (e *Encoding) UnmarshalJSON(b []byte) error {
+	*e = ParseEncoding(string(bytes.Trim(b, `"`)))
+
+	return nil
+}
+
+// NewWriter returns a writer compressing to the given writer at the level, 0 for the default level.
+// The writer must be closed to flush the compressed data.
+func (e Encoding) NewWriter(w io.Writer, level int) (io.WriteCloser, error) {
+	switch e {
+	case EncodingIdentity:
+		return nopWriteCloser{w}, nil
+	case EncodingGzip:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		return gzip.NewWriterLevel(w, level)
+	case EncodingDeflate:
+		if level == 0 {
+			level = zlib.DefaultCompression
+		}
+		return zlib.NewWriterLevel(w, level)
+	case EncodingBrotli:
+		if level == 0 {
+			level = brotli.DefaultCompression
+		}
+		return brotli.NewWriterLevel(w, level), nil
+	case EncodingZstd:
+		if level == 0 {
+			return zstd.NewWriter(w)
+		}
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	}
+
+	return nil, ErrEncodingInvalid
+}
+
+// NewReader returns a reader decompressing from the given reader. The reader must be closed to release its
+// resources.
+func (e Encoding) NewReader(r io.Reader) (io.ReadCloser, error) {
+	switch e {
+	case EncodingIdentity:
+		return io.NopCloser(r), nil
+	case EncodingGzip:
+		return gzip.NewReader(r)
+	case EncodingDeflate:
+		return zlib.NewReader(r)
+	case EncodingBrotli:
+		return io.NopCloser(brotli.NewReader(r)), nil
+	case EncodingZstd:
+		decoder, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	}
+
+	return nil, ErrEncodingInvalid
+}
+
+// ParseEncoding parses encoding string case-insensitively, e.g. of the Content-Encoding header.
+// An empty string is the identity encoding.
+func ParseEncoding(name string) Encoding {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "":
+		return EncodingIdentity
+	case "x-gzip":
+		return EncodingGzip
+	}
+
+	for k, v := range EncodingNames {
+		if v == name {
+			return k
+		}
+	}
+
+	return EncodingInvalid
+}
+
+// MustParseEncoding parses encoding string or panics.
+func MustParseEncoding(name string) Encoding {
+	v := ParseEncoding(name)
+	if v == EncodingInvalid {
+		panic(ErrEncodingInvalid)
+	}
+
+	return v
+}
+
+// NewEncodedType creates a new EncodedType wrapping the codec with the encoding by name, e.g. the value of the
+// Content-Encoding header.
+func NewEncodedType(codec IContentType, encoding string) (*EncodedType, error) {
+	e := ParseEncoding(encoding)
+	if e == EncodingInvalid {
+		return nil, ErrEncodingInvalid
+	}
+
+	return &EncodedType{Codec: codec, Encoding: e}, nil
+}
+
+// NegotiateEncodedType creates a new EncodedType wrapping the codec with the supported encoding best matching the
+// value of the Accept-Encoding header, or no encoding when the header is empty. ErrNotAcceptable is returned when none
+// is acceptable.
+func NegotiateEncodedType(codec IContentType, acceptEncoding string) (*EncodedType, error) {
+	offered := make([]string, len(Encodings))
+	for i, e := range Encodings {
+		offered[i] = e.String()
+	}
+
+	encoding, err := NegotiateEncoding(acceptEncoding, offered...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewEncodedType(codec, encoding)
+}
+
+// Marshal returns a reader of the given value marshaled by the Codec and compressed with the Encoding, encoded
+// lazily as the reader is consumed.
+func (et *EncodedType) Marshal(value any) (io.Reader, error) {
+	switch et.Encoding {
+	case EncodingIdentity:
+		return et.Codec.Marshal(value)
+	case EncodingInvalid:
+		return nil, ErrEncodingInvalid
+	}
+
+	return streamMarshal(et.Encode, value), nil
+}
+
+// Encode writes the given value marshaled by the Codec and compressed with the Encoding to the writer.
+func (et *EncodedType) Encode(w io.Writer, value any) error {
+	cw, err := et.Encoding.NewWriter(w, et.Level)
+	if err != nil {
+		return err
+	}
+
+	if err = Encode(et.Codec, cw, value); err != nil {
+		cw.Close()
+		return err
+	}
+
+	return cw.Close()
+}
+
+// Unmarshal decompresses the given reader with the Encoding and parses it with the Codec into the value.
+func (et *EncodedType) Unmarshal(r io.Reader, value any) error {
+	rc, err := et.Encoding.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return et.Codec.Unmarshal(rc, value)
+}
+
+// Headers returns the Content-Encoding header of the encoded body, none for the identity encoding.
+func (et *EncodedType) Headers() http.Headers {
+	headers := http.Headers{}
+	if et.Encoding != EncodingIdentity {
+		headers[http.HeaderContentEncoding] = et.Encoding.String()
+	}
+
+	return headers
+}
+
+// Close does nothing.
+func (nopWriteCloser) Close() error {
+	return nil
+}
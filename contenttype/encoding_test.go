@@ -0,0 +1,30 @@
+package contenttype_test
+
+import (
+	"testing"
+
+	"github.com/leliuga/data/contenttype"
+)
+
+func TestNegotiateEncodedType(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   contenttype.Encoding
+	}{
+		{"", contenttype.EncodingIdentity},
+		{"gzip", contenttype.EncodingGzip},
+		{"gzip;q=0.5, br", contenttype.EncodingBrotli},
+		{"*", contenttype.EncodingBrotli},
+	}
+
+	for _, tt := range tests {
+		et, err := contenttype.NegotiateEncodedType(&contenttype.JsonType{}, tt.accept)
+		if err != nil {
+			t.Fatalf("NegotiateEncodedType(%q) error = %v", tt.accept, err)
+		}
+
+		if et.Encoding != tt.want {
+			t.Fatalf("NegotiateEncodedType(%q) encoding = %v, want %v", tt.accept, et.Encoding, tt.want)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package contenttype
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NewUnsupportedTypeError creates a new UnsupportedTypeError for the type of the given value.
+func NewUnsupportedTypeError(ct ContentType, value any) *UnsupportedTypeError {
+	return &UnsupportedTypeError{
+		ContentType: ct,
+		Type:        reflect.TypeOf(value),
+	}
+}
+
+// Error returns the error message.
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("a data type %s is not supported by %s", e.Type, e.ContentType)
+}
@@ -0,0 +1,270 @@
+package contenttype
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	// maxEventLineSize is the maximum size in bytes of a line of a text/event-stream.
+	maxEventLineSize = 16 << 20
+)
+
+var (
+	// ErrEventInvalid is returned when the id or the event name of an event contains a line break.
+	ErrEventInvalid = errors.New("invalid server-sent event")
+)
+
+// Marshal returns a reader for the given events, encoded lazily as the reader is consumed.
+// The value must be a slice or an array, a channel read until it is closed, or an iterator func(yield func(T) bool).
+// A record other than an Event is sent as the data of an event, as is for a string and as json otherwise.
+// The encoding stops at the first error, which is returned by the reader. Closing the reader stops the encoding.
+func (et *EventStreamType) Marshal(value any) (io.Reader, error) {
+	if _, err := records(EventStream, value); err != nil {
+		return nil, err
+	}
+
+	return streamMarshal(et.Encode, value), nil
+}
+
+// Encode writes the given events to the writer, flushing it after each one, e.g. an http.ResponseWriter.
+// The value must be a slice or an array, a channel read until it is closed, or an iterator func(yield func(T) bool).
+func (et *EventStreamType) Encode(w io.Writer, value any) error {
+	rv, err := records(EventStream, value)
+	if err != nil {
+		return err
+	}
+
+	encoder := NewEventStreamEncoder(w)
+
+	return encodeRecords(rv, func(record any) error {
+		event, err := newEvent(record)
+		if err != nil {
+			return err
+		}
+
+		return encoder.Encode(event)
+	})
+}
+
+// Unmarshal parses the given reader and stores the events in the value.
+// The value must be a pointer to a slice, receiving all the events, a channel, receiving the events one at a time
+// and closed at the end, or a callback func(T) bool, called for each event until it returns false.
+// A record other than an Event receives the data of an event, as is for a string and as json otherwise.
+func (et *EventStreamType) Unmarshal(r io.Reader, value any) error {
+	decoder := NewEventStreamDecoder(r)
+
+	return decodeRecords(EventStream, value, func(record any) error {
+		if event, ok := record.(*Event); ok {
+			return decoder.Decode(event)
+		}
+
+		var event Event
+		if err := decoder.Decode(&event); err != nil {
+			return err
+		}
+
+		if s, ok := record.(*string); ok {
+			*s = event.Data
+			return nil
+		}
+
+		return json.Unmarshal([]byte(event.Data), record)
+	})
+}
+
+// NewEventStreamEncoder creates a new encoder writing to the given writer.
+func NewEventStreamEncoder(w io.Writer) *EventStreamEncoder {
+	return &EventStreamEncoder{w: w}
+}
+
+// Encode writes the event, with at least one data line, followed by a blank line and flushes the writer.
+func (e *EventStreamEncoder) Encode(event Event) error {
+	if strings.ContainsAny(event.ID, "\r\n\x00") || strings.ContainsAny(event.Event, "\r\n") {
+		return ErrEventInvalid
+	}
+
+	var b strings.Builder
+	if event.ID != "" {
+		b.WriteString("id: " + event.ID + "\n")
+	}
+	if event.Event != "" {
+		b.WriteString("event: " + event.Event + "\n")
+	}
+	if event.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(event.Retry.Milliseconds(), 10) + "\n")
+	}
+
+	// an event without a data line is never dispatched by the clients, so an empty data is still written
+	data := strings.ReplaceAll(strings.ReplaceAll(event.Data, "\r\n", "\n"), "\r", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	return e.write(b.String())
+}
+
+// Comment writes a comment, ignored by the clients, e.g. to keep the connection alive, and flushes the writer.
+func (e *EventStreamEncoder) Comment(text string) error {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r", ""), "\n") {
+		b.WriteString(": " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	return e.write(b.String())
+}
+
+// write writes the string and flushes the writer if it is a flusher.
+func (e *EventStreamEncoder) write(s string) error {
+	if _, err := io.WriteString(e.w, s); err != nil {
+		return err
+	}
+
+	switch f := e.w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+
+	return nil
+}
+
+// NewEventStreamDecoder creates a new decoder reading from the given reader.
+func NewEventStreamDecoder(r io.Reader) *EventStreamDecoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxEventLineSize)
+	scanner.Split(scanEventLines())
+
+	return &EventStreamDecoder{scanner: scanner}
+}
+
+// Decode reads the next event and stores it in the value pointed to by event, io.EOF is returned at the end.
+// The events are parsed as specified by the HTML Living Standard, an incomplete event at the end being discarded.
+func (d *EventStreamDecoder) Decode(event *Event) error {
+	var data strings.Builder
+	var hasData bool
+	*event = Event{}
+
+	for d.scanner.Scan() {
+		line := d.scanner.Text()
+		if line == "" {
+			if !hasData {
+				*event = Event{}
+				continue
+			}
+
+			event.ID = d.lastEventID
+			event.Data = strings.TrimSuffix(data.String(), "\n")
+
+			return nil
+		}
+
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data.WriteString(value + "\n")
+			hasData = true
+		case "id":
+			if !strings.Contains(value, "\x00") {
+				d.lastEventID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 63); err == nil {
+				event.Retry = time.Duration(ms) * time.Millisecond
+				d.retry = event.Retry
+			}
+		}
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return err
+	}
+
+	return io.EOF
+}
+
+// LastEventID returns the last event id, e.g. for the Last-Event-ID header on reconnection.
+func (d *EventStreamDecoder) LastEventID() string {
+	return d.lastEventID
+}
+
+// Retry returns the last reconnection time, including one sent in a block without data, or 0 if none.
+func (d *EventStreamDecoder) Retry() time.Duration {
+	return d.retry
+}
+
+// newEvent returns the record as an event, a record other than an Event being the data of the event.
+func newEvent(record any) (Event, error) {
+	switch v := record.(type) {
+	case Event:
+		return v, nil
+	case *Event:
+		return *v, nil
+	case string:
+		return Event{Data: v}, nil
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{Data: string(data)}, nil
+}
+
+// scanEventLines returns a split function for a bufio.Scanner splitting lines ended by "\r\n", "\n" or "\r",
+// without waiting for a "\n" after a "\r" so events are not delayed.
+func scanEventLines() bufio.SplitFunc {
+	skipLF, first := false, true
+
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		// a token is returned whenever possible, as the scanner stops at EOF on an advance without a token
+		skip := 0
+		if first && len(data) > 0 {
+			if !atEOF && len(data) < 3 && bytes.HasPrefix([]byte("\ufeff"), data) {
+				return 0, nil, nil
+			}
+
+			first = false
+			if bytes.HasPrefix(data, []byte("\ufeff")) {
+				skip = 3
+			}
+		}
+
+		if skipLF && len(data) > skip {
+			skipLF = false
+			if data[skip] == '\n' {
+				skip++
+			}
+		}
+
+		if i := bytes.IndexAny(data[skip:], "\r\n"); i > -1 {
+			skipLF = data[skip+i] == '\r'
+			return skip + i + 1, data[skip : skip+i], nil
+		}
+
+		if atEOF && len(data) > skip {
+			return len(data), data[skip:], nil
+		}
+
+		return skip, nil, nil
+	}
+}
@@ -0,0 +1,66 @@
+package contenttype_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leliuga/data/contenttype"
+)
+
+func TestEventStreamRetry(t *testing.T) {
+	decoder := contenttype.NewEventStreamDecoder(strings.NewReader("retry: 1500\n\ndata: hello\n\n"))
+
+	var event contenttype.Event
+	if err := decoder.Decode(&event); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if event.Data != "hello" {
+		t.Fatalf("Decode() data = %q, want %q", event.Data, "hello")
+	}
+
+	if retry := decoder.Retry(); retry != 1500*time.Millisecond {
+		t.Fatalf("Retry() = %v, want %v", retry, 1500*time.Millisecond)
+	}
+
+	if err := decoder.Decode(&event); err != io.EOF {
+		t.Fatalf("Decode() error = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestEventStreamRoundTrip(t *testing.T) {
+	in := []contenttype.Event{
+		{Event: "ping"},
+		{ID: "1"},
+		{Data: "x"},
+		{Event: "update", Data: "a\nb", Retry: time.Second},
+	}
+
+	et := &contenttype.EventStreamType{}
+	r, err := et.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var out []contenttype.Event
+	if err = et.Unmarshal(r, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := []contenttype.Event{
+		{Event: "ping"},
+		{ID: "1"},
+		{ID: "1", Data: "x"},
+		{ID: "1", Event: "update", Data: "a\nb", Retry: time.Second},
+	}
+	if len(out) != len(want) {
+		t.Fatalf("Unmarshal() = %+v, want %+v", out, want)
+	}
+	for i := range want {
+		if out[i] != want[i] {
+			t.Errorf("Unmarshal()[%d] = %+v, want %+v", i, out[i], want[i])
+		}
+	}
+}
@@ -2,13 +2,14 @@ package contenttype
 
 import (
 	"bytes"
-	"fmt"
 	"io"
 	"net/url"
 	"reflect"
 )
 
 // Marshal returns a reader for the given value.
+// The value must be a string, a url.Values, a map[string][]string, a map[string]string or a struct, or a pointer to
+// one, whose fields are bound as described by Unmarshal.
 func (ft *FormType) Marshal(value any) (io.Reader, error) {
 	v := ""
 	switch t := value.(type) {
@@ -16,26 +17,74 @@ func (ft *FormType) Marshal(value any) (io.Reader, error) {
 		v = t
 	case url.Values:
 		v = t.Encode()
+	case map[string][]string:
+		v = url.Values(t).Encode()
+	case map[string]string:
+		values := make(url.Values, len(t))
+		for key, value := range t {
+			values.Set(key, value)
+		}
+
+		v = values.Encode()
 	default:
-		return nil, fmt.Errorf("a data type %s is invalid", reflect.TypeOf(t))
+		rv := reflect.Indirect(reflect.ValueOf(value))
+		if rv.Kind() != reflect.Struct {
+			return nil, NewUnsupportedTypeError(Form, value)
+		}
+
+		values := make(url.Values)
+		if err := encodeForm(values, "", rv); err != nil {
+			return nil, err
+		}
+
+		v = values.Encode()
 	}
 
 	return bytes.NewBufferString(v), nil
 }
 
 // Unmarshal parses the given reader and stores the result in the value pointed to by value.
+// The value must be a *string, a *[]byte, a *url.Values, a *map[string][]string, an *any or a pointer to a struct.
+//
+// A struct field is bound by its `form` tag, or its name when the tag is missing, "-" skips the field and the
+// "omitempty" option skips a zero value when encoding. Nested structs use the dot notation `address.city` and slices of
+// structs the index notation `items[0].name`, the bracket notation `address[city]` and `items[0][name]` is accepted
+// when decoding. A slice of scalars is bound to the repeated key, time.Time to constants.DefaultDateTimeFormat.
 func (ft *FormType) Unmarshal(r io.Reader, value any) error {
 	b, err := io.ReadAll(r)
 	if err != nil {
 		return err
 	}
 
+	switch t := value.(type) {
+	case *string:
+		*t = string(b)
+		return nil
+	case *[]byte:
+		*t = b
+		return nil
+	}
+
 	v, err := url.ParseQuery(string(b))
 	if err != nil {
 		return err
 	}
 
-	value = v
+	switch t := value.(type) {
+	case *url.Values:
+		*t = v
+	case *map[string][]string:
+		*t = v
+	case *any:
+		*t = v
+	default:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+			return NewUnsupportedTypeError(Form, value)
+		}
+
+		return decodeForm(normalizeForm(v), "", rv.Elem())
+	}
 
 	return nil
 }
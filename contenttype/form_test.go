@@ -0,0 +1,190 @@
+package contenttype_test
+
+import (
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leliuga/data/constants"
+	"github.com/leliuga/data/contenttype"
+)
+
+type FormAddress struct {
+	City string `form:"city"`
+	Zip  *int   `form:"zip"`
+}
+
+type FormItem struct {
+	Name  string `form:"name"`
+	Count int    `form:"count"`
+}
+
+type FormBase struct {
+	ID int `form:"id"`
+}
+
+type FormUser struct {
+	FormBase
+	Name     string       `form:"name"`
+	Nickname string       `form:"nickname,omitempty"`
+	Secret   string       `form:"-"`
+	Title    string       // bound by its name
+	Age      *int         `form:"age"`
+	Address  FormAddress  `form:"address"`
+	Billing  *FormAddress `form:"billing"`
+	Tags     []string     `form:"tags"`
+	Scores   []int        `form:"scores"`
+	Items    []FormItem   `form:"items"`
+	Born     time.Time    `form:"born"`
+	Active   bool         `form:"active"`
+}
+
+func marshalForm(t *testing.T, value any) string {
+	t.Helper()
+
+	r, err := (&contenttype.FormType{}).Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	return string(b)
+}
+
+func unmarshalForm(t *testing.T, body string, value any) {
+	t.Helper()
+
+	if err := (&contenttype.FormType{}).Unmarshal(strings.NewReader(body), value); err != nil {
+		t.Fatalf("Unmarshal(%q) error = %v", body, err)
+	}
+}
+
+func TestFormRoundTrip(t *testing.T) {
+	age, zip := 42, 1000
+	born := time.Date(1990, 5, 17, 8, 30, 0, 0, time.UTC)
+	in := FormUser{
+		FormBase: FormBase{ID: 7},
+		Name:     "Ada",
+		Secret:   "hidden",
+		Title:    "Dr",
+		Age:      &age,
+		Address:  FormAddress{City: "Vilnius", Zip: &zip},
+		Tags:     []string{"a", "b"},
+		Scores:   []int{3, 1},
+		Items:    []FormItem{{Name: "x", Count: 1}, {Name: "y", Count: 2}},
+		Born:     born,
+		Active:   true,
+	}
+
+	body := marshalForm(t, in)
+	values, err := url.ParseQuery(body)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+
+	want := url.Values{
+		"id":             {"7"},
+		"name":           {"Ada"},
+		"Title":          {"Dr"},
+		"age":            {"42"},
+		"address.city":   {"Vilnius"},
+		"address.zip":    {"1000"},
+		"tags":           {"a", "b"},
+		"scores":         {"3", "1"},
+		"items[0].name":  {"x"},
+		"items[0].count": {"1"},
+		"items[1].name":  {"y"},
+		"items[1].count": {"2"},
+		"born":           {born.Format(constants.DefaultDateTimeFormat)},
+		"active":         {"true"},
+	}
+
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("Marshal() = %v, want %v", values, want)
+	}
+
+	var out FormUser
+	unmarshalForm(t, body, &out)
+
+	in.Secret = ""
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("Unmarshal() = %+v, want %+v", out, in)
+	}
+}
+
+func TestFormOmitEmpty(t *testing.T) {
+	body := marshalForm(t, FormUser{Nickname: ""})
+	if strings.Contains(body, "nickname") {
+		t.Errorf("Marshal() = %q, want no nickname", body)
+	}
+
+	body = marshalForm(t, FormUser{Nickname: "ace"})
+	if !strings.Contains(body, "nickname=ace") {
+		t.Errorf("Marshal() = %q, want nickname=ace", body)
+	}
+}
+
+func TestFormNotations(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want FormUser
+	}{
+		{"dot", "address.city=Riga", FormUser{Address: FormAddress{City: "Riga"}}},
+		{"bracket", "address[city]=Riga", FormUser{Address: FormAddress{City: "Riga"}}},
+		{"repeated key", "tags=a&tags=b", FormUser{Tags: []string{"a", "b"}}},
+		{"empty brackets", "tags[]=a&tags[]=b&scores[]=5", FormUser{Tags: []string{"a", "b"}, Scores: []int{5}}},
+		{"indexed dot", "items[0].name=x&items[1].count=2", FormUser{Items: []FormItem{{Name: "x"}, {Count: 2}}}},
+		{"indexed bracket", "items[0][name]=x&items[0][count]=3", FormUser{Items: []FormItem{{Name: "x", Count: 3}}}},
+		{"sparse indexes", "items[5][name]=b&items[2][name]=a", FormUser{Items: []FormItem{{Name: "a"}, {Name: "b"}}}},
+		{"skipped field", "Secret=s&-=s", FormUser{}},
+		{"embedded", "id=9", FormUser{FormBase: FormBase{ID: 9}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out FormUser
+			unmarshalForm(t, tt.body, &out)
+
+			if !reflect.DeepEqual(out, tt.want) {
+				t.Errorf("Unmarshal(%q) = %+v, want %+v", tt.body, out, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormPointers(t *testing.T) {
+	var out FormUser
+	unmarshalForm(t, "name=a", &out)
+	if out.Age != nil || out.Billing != nil || out.Address.Zip != nil {
+		t.Errorf("Unmarshal() = %+v, want nil pointers without values", out)
+	}
+
+	unmarshalForm(t, "age=3&billing[zip]=10", &out)
+	if out.Age == nil || *out.Age != 3 {
+		t.Errorf("Unmarshal() Age = %v, want 3", out.Age)
+	}
+	if out.Billing == nil || out.Billing.Zip == nil || *out.Billing.Zip != 10 {
+		t.Errorf("Unmarshal() Billing = %+v, want zip 10", out.Billing)
+	}
+}
+
+func TestFormTime(t *testing.T) {
+	var out FormUser
+	unmarshalForm(t, "born=2001-02-03+04%3A05%3A06", &out)
+
+	if want := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC); !out.Born.Equal(want) {
+		t.Errorf("Unmarshal() Born = %v, want %v", out.Born, want)
+	}
+
+	if err := (&contenttype.FormType{}).Unmarshal(strings.NewReader("born=yesterday"), &out); err == nil {
+		t.Errorf("Unmarshal() error = nil, want an error for an invalid time")
+	}
+}
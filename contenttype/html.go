@@ -2,23 +2,148 @@ package contenttype
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"html/template"
 	"io"
+	"io/fs"
 )
 
-// Marshal returns a reader for the given value.
+var (
+	// ErrHtmlTemplateNotFound is returned when the template to render is not found.
+	ErrHtmlTemplateNotFound = errors.New("html template not found")
+)
+
+// Marshal returns a reader for the given value rendered with its template, buffered so a rendering error is
+// returned instead of a partial document.
 func (ht *HtmlType) Marshal(value any) (io.Reader, error) {
-	return bytes.NewBufferString(fmt.Sprintf("%v", value)), nil
+	buffer := bytes.NewBuffer(nil)
+	if err := ht.Encode(buffer, value); err != nil {
+		return nil, err
+	}
+
+	return buffer, nil
+}
+
+// Encode writes the given value rendered with its template to the writer. The template is the one of an HtmlView,
+// the Template of the HtmlType otherwise. Without a template, the value is written as is.
+func (ht *HtmlType) Encode(w io.Writer, value any) error {
+	view, ok := value.(HtmlView)
+	if v, isPointer := value.(*HtmlView); isPointer && v != nil {
+		view, ok = *v, true
+	}
+
+	if !ok {
+		if ht.Template == "" {
+			_, err := fmt.Fprintf(w, "%v", value)
+			return err
+		}
+
+		view = HtmlView{Template: ht.Template, Data: value}
+	}
+
+	if view.Layout == "" {
+		view.Layout = ht.Layout
+	}
+
+	return ht.render(w, view)
 }
 
 // Unmarshal parses the given reader and stores the result in the value pointed to by value.
+// The value must be a *string, a *[]byte, an encoding.TextUnmarshaler or an *any.
 func (ht *HtmlType) Unmarshal(r io.Reader, value any) error {
-	b, err := io.ReadAll(r)
+	return unmarshalText(Html, r, value)
+}
+
+// render executes the layout of the view, or its template without a layout, with the data of the view.
+func (ht *HtmlType) render(w io.Writer, view HtmlView) error {
+	ht.once.Do(func() {
+		ht.base, ht.pages, ht.err = ht.parse()
+	})
+	if ht.err != nil {
+		return ht.err
+	}
+
+	set, ok := ht.pages[view.Template]
+	if !ok {
+		if ht.base == nil || ht.base.Lookup(view.Template) == nil {
+			return fmt.Errorf("%w: %q", ErrHtmlTemplateNotFound, view.Template)
+		}
+
+		set = ht.base
+	}
+
+	name := view.Template
+	if view.Layout != "" {
+		name = view.Layout
+	}
+
+	return set.ExecuteTemplate(w, name, view.Data)
+}
+
+// parse parses the layouts and partials into a base set of templates, cloned for each page so the pages can
+// define the same blocks of a layout.
+func (ht *HtmlType) parse() (*template.Template, map[string]*template.Template, error) {
+	if ht.FS == nil {
+		return nil, nil, nil
+	}
+
+	base := template.New("").Funcs(ht.Funcs)
+	shared := make(map[string]bool)
+	for _, pattern := range ht.Layouts {
+		files, err := fs.Glob(ht.FS, pattern)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, file := range files {
+			if err = parseHtmlTemplate(ht.FS, base, file); err != nil {
+				return nil, nil, err
+			}
+			shared[file] = true
+		}
+	}
+
+	patterns := ht.Pages
+	if len(patterns) == 0 {
+		patterns = []string{"*.html"}
+	}
+
+	pages := make(map[string]*template.Template)
+	for _, pattern := range patterns {
+		files, err := fs.Glob(ht.FS, pattern)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, file := range files {
+			if shared[file] {
+				continue
+			}
+
+			set, err := base.Clone()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if err = parseHtmlTemplate(ht.FS, set, file); err != nil {
+				return nil, nil, err
+			}
+			pages[file] = set
+		}
+	}
+
+	return base, pages, nil
+}
+
+// parseHtmlTemplate parses the file of the file system as a template named by its path in the set.
+func parseHtmlTemplate(fsys fs.FS, set *template.Template, file string) error {
+	b, err := fs.ReadFile(fsys, file)
 	if err != nil {
 		return err
 	}
 
-	value = string(b)
+	_, err = set.New(file).Parse(string(b))
 
-	return nil
+	return err
 }
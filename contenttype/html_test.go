@@ -0,0 +1,130 @@
+package contenttype_test
+
+import (
+	"errors"
+	"html/template"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/leliuga/data/contenttype"
+)
+
+func htmlFS() fstest.MapFS {
+	return fstest.MapFS{
+		"layouts/base.html":   {Data: []byte(`<title>{{block "title" .}}Site{{end}}</title><main>{{template "content" .}}</main>{{template "footer"}}`)},
+		"layouts/footer.html": {Data: []byte(`{{define "footer"}}<footer>{{upper "end"}}</footer>{{end}}`)},
+		"home.html":           {Data: []byte(`{{define "title"}}Home{{end}}{{define "content"}}<p>{{.}}</p>{{end}}`)},
+		"about.html":          {Data: []byte(`{{define "content"}}<p>About {{.}}</p>{{end}}`)},
+		"plain.html":          {Data: []byte(`<p>{{.}}</p>`)},
+	}
+}
+
+func renderHtml(t *testing.T, ht *contenttype.HtmlType, value any) string {
+	t.Helper()
+
+	r, err := ht.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	return string(b)
+}
+
+func newHtmlType(fsys fstest.MapFS) *contenttype.HtmlType {
+	return &contenttype.HtmlType{
+		FS:      fsys,
+		Layouts: []string{"layouts/*.html"},
+		Layout:  "layouts/base.html",
+		Funcs:   template.FuncMap{"upper": strings.ToUpper},
+	}
+}
+
+func TestHtmlLayout(t *testing.T) {
+	ht := newHtmlType(htmlFS())
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"page blocks", contenttype.HtmlView{Template: "home.html", Data: "hi"},
+			"<title>Home</title><main><p>hi</p></main><footer>END</footer>"},
+		{"default block", &contenttype.HtmlView{Template: "about.html", Data: "us"},
+			"<title>Site</title><main><p>About us</p></main><footer>END</footer>"},
+		{"auto-escaping", contenttype.HtmlView{Template: "home.html", Data: `<script>alert("x")</script>`},
+			"<title>Home</title><main><p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</p></main><footer>END</footer>"},
+		{"shared template", contenttype.HtmlView{Template: "footer", Layout: "footer"},
+			"<footer>END</footer>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderHtml(t, ht, tt.value); got != tt.want {
+				t.Errorf("Marshal() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHtmlTemplateOption(t *testing.T) {
+	ht := newHtmlType(htmlFS())
+	ht.Template = "about.html"
+
+	if got, want := renderHtml(t, ht, "me"), "<title>Site</title><main><p>About me</p></main><footer>END</footer>"; got != want {
+		t.Errorf("Marshal() = %q, want %q", got, want)
+	}
+
+	// an HtmlView takes precedence over the Template option
+	view := contenttype.HtmlView{Template: "home.html", Data: "me"}
+	if got, want := renderHtml(t, ht, view), "<title>Home</title><main><p>me</p></main><footer>END</footer>"; got != want {
+		t.Errorf("Marshal() = %q, want %q", got, want)
+	}
+}
+
+func TestHtmlWithoutLayout(t *testing.T) {
+	ht := newHtmlType(htmlFS())
+	ht.Layout = ""
+
+	if got, want := renderHtml(t, ht, contenttype.HtmlView{Template: "plain.html", Data: "raw"}), "<p>raw</p>"; got != want {
+		t.Errorf("Marshal() = %q, want %q", got, want)
+	}
+}
+
+func TestHtmlWithoutTemplate(t *testing.T) {
+	if got, want := renderHtml(t, &contenttype.HtmlType{}, "<b>as is</b>"), "<b>as is</b>"; got != want {
+		t.Errorf("Marshal() = %q, want %q", got, want)
+	}
+}
+
+func TestHtmlTemplateNotFound(t *testing.T) {
+	ht := newHtmlType(htmlFS())
+
+	_, err := ht.Marshal(contenttype.HtmlView{Template: "missing.html"})
+	if !errors.Is(err, contenttype.ErrHtmlTemplateNotFound) {
+		t.Errorf("Marshal() error = %v, want %v", err, contenttype.ErrHtmlTemplateNotFound)
+	}
+}
+
+func TestHtmlParseError(t *testing.T) {
+	fsys := htmlFS()
+	fsys["broken.html"] = &fstest.MapFile{Data: []byte(`{{define "content"}}`)}
+	ht := newHtmlType(fsys)
+
+	_, first := ht.Marshal(contenttype.HtmlView{Template: "home.html"})
+	if first == nil {
+		t.Fatalf("Marshal() error = nil, want a parse error")
+	}
+
+	// the templates are parsed once, so fixing the file system does not clear the error
+	fsys["broken.html"] = &fstest.MapFile{Data: []byte(`{{define "content"}}fixed{{end}}`)}
+	if _, err := ht.Marshal(contenttype.HtmlView{Template: "home.html"}); err == nil || err.Error() != first.Error() {
+		t.Errorf("Marshal() error = %v, want the cached %v", err, first)
+	}
+}
@@ -2,22 +2,193 @@ package contenttype
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/goccy/go-json"
 )
 
-// Marshal returns a reader for the given value.
-func (jt *JsonType) Marshal(value any) (io.Reader, error) {
-	buffer := bytes.NewBuffer(nil)
-	if err := json.NewEncoder(buffer).Encode(value); err != nil {
-		return nil, err
+type (
+	// jsonDepthReader is a reader failing with ErrMaxDepth when the nesting depth of the json read exceeds max.
+	jsonDepthReader struct {
+		r        io.Reader
+		max      int
+		depth    int
+		offset   int64
+		inString bool
+		escaped  bool
+		err      error // kept as decoders may not return the errors of the reader as is
 	}
+)
+
+// Marshal returns a reader for the given value, encoded as the reader is consumed. An encoding error is returned
+// before the first byte is written, a later one by the reader. The reader is an io.ReadCloser, to be read until EOF
+// or closed to stop the encoding, and the value must not be mutated until then.
+func (jt *JsonType) Marshal(value any) (io.Reader, error) {
+	return startMarshal(jt.Encode, value)
+}
 
-	return buffer, nil
+// Encode writes the encoding of the given value to the writer.
+func (jt *JsonType) Encode(w io.Writer, value any) error {
+	return json.NewEncoder(w).Encode(value)
 }
 
 // Unmarshal parses the given reader and stores the result in the value pointed to by value.
+// A failure is returned as a DecodeError, locating unknown fields and mismatched types by their path.
 func (jt *JsonType) Unmarshal(r io.Reader, value any) error {
-	return json.NewDecoder(r).Decode(value)
+	lr := newLimitedReader(r, jt.MaxSize)
+	dr := &jsonDepthReader{r: lr, max: jt.MaxDepth}
+	r = dr
+
+	// the body is kept to locate the failures by their path
+	body := bytes.NewBuffer(nil)
+	r = io.TeeReader(r, body)
+
+	decoder := json.NewDecoder(r)
+	if jt.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if jt.UseNumber {
+		decoder.UseNumber()
+	}
+
+	if err := decoder.Decode(value); err != nil {
+		switch {
+		case lr.err != nil:
+			return &DecodeError{ContentType: Json, Offset: lr.max, Err: lr.err}
+		case dr.err != nil:
+			return dr.err
+		}
+
+		de := newDecodeError(Json, err)
+
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			de.Offset = syntaxErr.Offset
+		case errors.As(err, &typeErr):
+			de.Offset = typeErr.Offset
+			de.Path = jsonPath(body.Bytes(), typeErr.Offset)
+		}
+
+		if key, ok := unknownField(err); ok {
+			var tree any
+			if json.Unmarshal(body.Bytes(), &tree) == nil {
+				de.Path, _ = locateUnknownField(tree, reflect.TypeOf(value), "json", key, "$")
+			}
+		}
+
+		return de
+	}
+
+	return nil
+}
+
+// jsonPath returns the path of the value starting at the offset of the json data, e.g. "$.items[1].name", or an
+// empty string if none.
+func jsonPath(data []byte, offset int64) string {
+	type frame struct {
+		object    bool
+		expectKey bool
+		key       string
+		index     int
+	}
+
+	var frames []*frame
+	next := func() {
+		if len(frames) == 0 {
+			return
+		}
+
+		if f := frames[len(frames)-1]; f.object {
+			f.expectKey = true
+		} else {
+			f.index++
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		end := decoder.InputOffset()
+
+		if n := len(frames); n > 0 && frames[n-1].object && frames[n-1].expectKey {
+			if key, ok := token.(string); ok {
+				frames[n-1].key, frames[n-1].expectKey = key, false
+				continue
+			}
+		}
+
+		if delim, ok := token.(json.Delim); ok && (delim == '}' || delim == ']') {
+			frames = frames[:len(frames)-1]
+			next()
+			continue
+		}
+
+		if offset >= start && offset < end {
+			var b strings.Builder
+			b.WriteString("$")
+			for _, f := range frames {
+				if f.object {
+					b.WriteString("." + f.key)
+				} else {
+					b.WriteString("[" + strconv.Itoa(f.index) + "]")
+				}
+			}
+
+			return b.String()
+		}
+
+		switch token {
+		case json.Delim('{'):
+			frames = append(frames, &frame{object: true, expectKey: true})
+		case json.Delim('['):
+			frames = append(frames, &frame{})
+		default:
+			next()
+		}
+	}
+}
+
+// Read reads up to len(p) bytes into p, tracking the nesting depth of the json objects and arrays.
+func (dr *jsonDepthReader) Read(p []byte) (int, error) {
+	if dr.max <= 0 {
+		return dr.r.Read(p)
+	}
+
+	if dr.err != nil {
+		return 0, dr.err
+	}
+
+	n, err := dr.r.Read(p)
+	for i, c := range p[:n] {
+		switch {
+		case dr.escaped:
+			dr.escaped = false
+		case dr.inString:
+			dr.escaped = c == '\\'
+			dr.inString = c != '"'
+		case c == '"':
+			dr.inString = true
+		case c == '{' || c == '[':
+			if dr.depth++; dr.depth > dr.max {
+				dr.err = &DecodeError{ContentType: Json, Offset: dr.offset + int64(i), Err: ErrMaxDepth}
+				return i, dr.err
+			}
+		case c == '}' || c == ']':
+			dr.depth--
+		}
+	}
+
+	dr.offset += int64(n)
+
+	return n, err
 }
@@ -0,0 +1,168 @@
+package contenttype
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+)
+
+// ParseMediaType parses a media type, e.g. a Content-Type header value, according to RFC 2045 and RFC 2231.
+// Malformed parameters are dropped, e.g. "application/json; charset" is parsed as "application/json".
+func ParseMediaType(s string) (*MediaType, error) {
+	essence, params, err := mime.ParseMediaType(s)
+	if errors.Is(err, mime.ErrInvalidMediaParameter) {
+		params, err = parseMediaParams(essence, s), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+
+	typ, subtype, ok := strings.Cut(essence, "/")
+	if !ok || typ == "" || subtype == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+
+	mt := &MediaType{
+		Type:    typ,
+		Subtype: subtype,
+		Params:  params,
+	}
+
+	if i := strings.LastIndexByte(subtype, '+'); i > -1 {
+		mt.Suffix = subtype[i+1:]
+	}
+
+	return mt, nil
+}
+
+// parseMediaParams returns the well-formed parameters of the media type, parsed one at a time.
+func parseMediaParams(essence, s string) map[string]string {
+	params := make(map[string]string)
+	_, rest, _ := strings.Cut(s, ";")
+	for _, param := range strings.Split(rest, ";") {
+		if _, p, err := mime.ParseMediaType(essence + ";" + param); err == nil {
+			for k, v := range p {
+				params[k] = v
+			}
+		}
+	}
+
+	return params
+}
+
+// MustParseMediaType parses a media type or panics.
+func MustParseMediaType(s string) *MediaType {
+	mt, err := ParseMediaType(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return mt
+}
+
+// Essence returns the media type without its parameters, e.g. "application/json".
+func (mt *MediaType) Essence() string {
+	return mt.Type + "/" + mt.Subtype
+}
+
+// String returns the media type with its parameters.
+func (mt *MediaType) String() string {
+	return mime.FormatMediaType(mt.Essence(), mt.Params)
+}
+
+// Param returns the value of the parameter by case-insensitive name or empty string.
+func (mt *MediaType) Param(name string) string {
+	return mt.Params[strings.ToLower(name)]
+}
+
+// Charset returns the charset parameter or empty string.
+func (mt *MediaType) Charset() string {
+	return mt.Param("charset")
+}
+
+// ContentType returns the content type of the media type or Invalid. A media type with a structured syntax
+// suffix, e.g. "application/problem+json", falls back to the content type of the suffix.
+func (mt *MediaType) ContentType() ContentType {
+	if ct := essenceContentType(mt.Essence()); ct != Invalid {
+		return ct
+	}
+
+	if mt.Suffix != "" {
+		return essenceContentType("application/" + mt.Suffix)
+	}
+
+	return Invalid
+}
+
+// Codec returns the codec registered for the media type in the DefaultRegistry, configured with the
+// charset of XML and the boundary of multipart media types.
+func (mt *MediaType) Codec() (IContentType, bool) {
+	codec, ok := DefaultRegistry.Lookup(mt.Essence())
+	if !ok {
+		return nil, false
+	}
+
+	switch c := codec.(type) {
+	case *XmlType:
+		xt := *c
+		if charset := mt.Charset(); charset != "" {
+			xt.Charset = charset
+		}
+		return &xt, true
+	case *MultipartType:
+		mpt := *c
+		if boundary := mt.Param("boundary"); boundary != "" {
+			mpt.Boundary = boundary
+		}
+		return &mpt, true
+	}
+
+	return codec, true
+}
+
+// NewReader returns a reader transcoding the body from the charset of the media type to UTF-8.
+// XML bodies are returned as is, since the codec transcodes them from their declared encoding, or from the charset
+// of the media type without a declaration.
+func (mt *MediaType) NewReader(r io.Reader) (io.Reader, error) {
+	if mt.Subtype == "xml" || mt.Suffix == "xml" {
+		return r, nil
+	}
+
+	return NewCharsetReader(mt.Charset(), r)
+}
+
+// Unmarshal transcodes the body to UTF-8 and parses it with the codec of the media type.
+func (mt *MediaType) Unmarshal(r io.Reader, value any) error {
+	codec, ok := mt.Codec()
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalid, mt.Essence())
+	}
+
+	r, err := mt.NewReader(r)
+	if err != nil {
+		return err
+	}
+
+	return codec.Unmarshal(r, value)
+}
+
+// essenceContentType returns the content type of the media type without parameters or its aliases, or Invalid.
+func essenceContentType(essence string) ContentType {
+	for ct, name := range Names {
+		if name == essence {
+			return ct
+		}
+	}
+
+	for ct, names := range aliases {
+		for _, name := range names {
+			if name == essence {
+				return ct
+			}
+		}
+	}
+
+	return Invalid
+}
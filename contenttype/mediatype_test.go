@@ -0,0 +1,54 @@
+package contenttype_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/leliuga/data/contenttype"
+)
+
+func TestParseMediaType(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		params map[string]string
+	}{
+		{"application/json", "application/json", map[string]string{}},
+		{"Application/JSON; Charset=UTF-8", "application/json", map[string]string{"charset": "UTF-8"}},
+		{"application/json; charset", "application/json", map[string]string{}},
+		{"text/html; charset; level=1", "text/html", map[string]string{"level": "1"}},
+		{"application/problem+json", "application/problem+json", map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		mt, err := contenttype.ParseMediaType(tt.in)
+		if err != nil {
+			t.Fatalf("ParseMediaType(%q) error = %v", tt.in, err)
+		}
+
+		if mt.Essence() != tt.want || !reflect.DeepEqual(mt.Params, tt.params) {
+			t.Errorf("ParseMediaType(%q) = %q %v, want %q %v", tt.in, mt.Essence(), mt.Params, tt.want, tt.params)
+		}
+	}
+
+	if _, err := contenttype.ParseMediaType("json"); err == nil {
+		t.Errorf("ParseMediaType(%q) error = nil, want an error", "json")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]contenttype.ContentType{
+		"application/json":                contenttype.Json,
+		"application/json; charset":       contenttype.Json,
+		"application/json; charset=utf-8": contenttype.Json,
+		"text/json":                       contenttype.Json,
+		"application/xml; =x":             contenttype.Xml,
+		"invalid":                         contenttype.Invalid,
+	}
+
+	for in, want := range tests {
+		if got := contenttype.Parse(in); got != want {
+			t.Errorf("Parse(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
@@ -2,22 +2,176 @@ package contenttype
 
 import (
 	"bytes"
+	"encoding/binary"
 	"io"
+	"reflect"
 
 	"github.com/vmihailenco/msgpack/v5"
 )
 
-// Marshal returns a reader for the given value.
+// Marshal returns a reader for the given value, encoded as the reader is consumed. An encoding error is returned
+// before the first byte is written, a later one by the reader. The reader is an io.ReadCloser, to be read until EOF
+// or closed to stop the encoding, and the value must not be mutated until then.
 func (mt *MsgPackType) Marshal(value any) (io.Reader, error) {
-	buffer := bytes.NewBuffer(nil)
-	if err := msgpack.NewEncoder(buffer).Encode(value); err != nil {
-		return nil, err
-	}
+	return startMarshal(mt.Encode, value)
+}
 
-	return buffer, nil
+// Encode writes the encoding of the given value to the writer.
+func (mt *MsgPackType) Encode(w io.Writer, value any) error {
+	encoder := msgpack.NewEncoder(w)
+	encoder.UseArrayEncodedStructs(mt.UseArrayEncodedStructs)
+
+	return encoder.Encode(value)
 }
 
 // Unmarshal parses the given reader and stores the result in the value pointed to by value.
+// A failure is returned as a DecodeError, locating unknown fields by their path.
 func (mt *MsgPackType) Unmarshal(r io.Reader, value any) error {
-	return msgpack.NewDecoder(r).Decode(value)
+	lr := newLimitedReader(r, mt.MaxSize)
+	r = lr
+
+	var data []byte
+	if mt.MaxDepth > 0 || mt.DisallowUnknownFields {
+		var err error
+		if data, err = io.ReadAll(lr); err != nil {
+			return &DecodeError{ContentType: MsgPack, Offset: lr.read, Err: err}
+		}
+
+		if offset := msgpackDepthOffset(data, mt.MaxDepth); offset > -1 {
+			return &DecodeError{ContentType: MsgPack, Offset: offset, Err: ErrMaxDepth}
+		}
+
+		r = bytes.NewReader(data)
+	}
+
+	decoder := msgpack.NewDecoder(r)
+	decoder.DisallowUnknownFields(mt.DisallowUnknownFields)
+
+	if err := decoder.Decode(value); err != nil {
+		if lr.err != nil {
+			return &DecodeError{ContentType: MsgPack, Offset: lr.max, Err: lr.err}
+		}
+
+		de := newDecodeError(MsgPack, err)
+		if key, ok := unknownField(err); ok && data != nil {
+			var tree any
+			if msgpack.Unmarshal(data, &tree) == nil {
+				de.Path, _ = locateUnknownField(tree, reflect.TypeOf(value), "msgpack", key, "$")
+			}
+		}
+
+		return de
+	}
+
+	return nil
+}
+
+// msgpackDepthOffset returns the offset of the first map or array of the msgpack data nested deeper than max,
+// or -1 if none or max is 0. Malformed data is left to the decoder to report.
+func msgpackDepthOffset(data []byte, max int) int64 {
+	if max <= 0 {
+		return -1
+	}
+
+	var remaining []uint64
+	for offset := 0; offset < len(data); {
+		c := data[offset]
+		size, count, container := msgpackHeader(data[offset:])
+		if size == 0 {
+			return -1
+		}
+
+		if container {
+			if len(remaining)+1 > max {
+				return int64(offset)
+			}
+			if c >= 0x80 && c <= 0x8f || c == 0xde || c == 0xdf {
+				count *= 2
+			}
+		}
+
+		offset += size
+		if container && count > 0 {
+			remaining = append(remaining, count)
+			continue
+		}
+
+		for len(remaining) > 0 {
+			if remaining[len(remaining)-1]--; remaining[len(remaining)-1] > 0 {
+				break
+			}
+			remaining = remaining[:len(remaining)-1]
+		}
+	}
+
+	return -1
+}
+
+// msgpackHeader returns the size in bytes of the msgpack value at the start of data, excluding the elements of a
+// map or an array, their count and whether it is a map or an array. The size is 0 for malformed data.
+func msgpackHeader(data []byte) (size int, count uint64, container bool) {
+	length := func(n int) uint64 {
+		if len(data) < 1+n {
+			return 0
+		}
+		switch n {
+		case 1:
+			return uint64(data[1])
+		case 2:
+			return uint64(binary.BigEndian.Uint16(data[1:]))
+		}
+		return uint64(binary.BigEndian.Uint32(data[1:]))
+	}
+	variable := func(n, extra int) int {
+		l := length(n)
+		if len(data) < 1+n || l > uint64(len(data)) {
+			return 0
+		}
+		return 1 + n + extra + int(l)
+	}
+
+	switch c := data[0]; {
+	case c <= 0x7f, c >= 0xe0, c == 0xc0, c == 0xc2, c == 0xc3:
+		size = 1
+	case c <= 0x9f:
+		return 1, uint64(c & 0x0f), true
+	case c <= 0xbf:
+		size = 1 + int(c&0x1f)
+	case c == 0xc4 || c == 0xd9:
+		size = variable(1, 0)
+	case c == 0xc5 || c == 0xda:
+		size = variable(2, 0)
+	case c == 0xc6 || c == 0xdb:
+		size = variable(4, 0)
+	case c == 0xc7:
+		size = variable(1, 1)
+	case c == 0xc8:
+		size = variable(2, 1)
+	case c == 0xc9:
+		size = variable(4, 1)
+	case c == 0xca:
+		size = 5
+	case c == 0xcb:
+		size = 9
+	case c >= 0xcc && c <= 0xd3:
+		size = 1 + 1<<((c-0xcc)%4)
+	case c >= 0xd4 && c <= 0xd8:
+		size = 2 + 1<<(c-0xd4)
+	case c == 0xdc || c == 0xde:
+		if len(data) < 3 {
+			return 0, 0, false
+		}
+		return 3, length(2), true
+	case c == 0xdd || c == 0xdf:
+		if len(data) < 5 {
+			return 0, 0, false
+		}
+		return 5, length(4), true
+	}
+
+	if size > len(data) {
+		return 0, 0, false
+	}
+
+	return size, 0, false
 }
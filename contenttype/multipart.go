@@ -0,0 +1,366 @@
+package contenttype
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultMultipartMaxMemory is the default number of bytes of the file parts kept in memory when decoding.
+	DefaultMultipartMaxMemory = 32 << 20
+)
+
+var (
+	// ErrMultipartBoundary is returned when the boundary of a multipart body can not be found.
+	ErrMultipartBoundary = errors.New("multipart boundary not found")
+
+	// ErrMultipartMaxMemory is returned when file parts over the maximum memory are decoded into a struct without a
+	// *multipart.Form field, since nothing could remove their temporary files.
+	ErrMultipartMaxMemory = errors.New("multipart file parts exceed the maximum memory")
+
+	multipartFileType        = reflect.TypeOf(MultipartFile{})
+	multipartFormType        = reflect.TypeOf((*multipart.Form)(nil))
+	multipartFileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	multipartQuoteReplacer   = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+	multipartFileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// Marshal returns a reader for the given value, the body is encoded lazily as the reader is consumed.
+// The value must be a url.Values, a map[string][]string, a map[string]string, a map[string]any or a struct, or a
+// pointer to one, whose MultipartFile, *MultipartFile and []MultipartFile values are encoded as file parts and the
+// others as form fields. The returned reader is a *MultipartBody.
+func (mt *MultipartType) Marshal(value any) (io.Reader, error) {
+	values, files, err := multipartParts(value)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	if mt.Boundary != "" {
+		if err = w.SetBoundary(mt.Boundary); err != nil {
+			return nil, err
+		}
+	}
+
+	go func() {
+		pw.CloseWithError(writeMultipart(w, values, files))
+	}()
+
+	return &MultipartBody{Reader: pr, boundary: w.Boundary()}, nil
+}
+
+// Unmarshal parses the given reader and stores the result in the value pointed to by value.
+// The value must be a *multipart.Form, a *url.Values, a *map[string][]string or a pointer to a struct whose fields are
+// bound as by FormType, *multipart.FileHeader and []*multipart.FileHeader fields receiving the file parts.
+// File parts over MaxMemory are stored in temporary files, to be removed by the RemoveAll method of the
+// *multipart.Form, either the value or a *multipart.Form field of the struct receiving the parsed form. For a struct
+// with file fields but no *multipart.Form field, ErrMultipartMaxMemory is returned instead and the temporary files
+// are removed.
+func (mt *MultipartType) Unmarshal(r io.Reader, value any) error {
+	boundary := mt.Boundary
+	if boundary == "" {
+		br := bufio.NewReader(r)
+		line, err := br.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return ErrMultipartBoundary
+		}
+
+		delimiter := strings.TrimRight(line, " \t\r\n")
+		if !strings.HasPrefix(delimiter, "--") || len(delimiter) == 2 {
+			return ErrMultipartBoundary
+		}
+
+		boundary = delimiter[2:]
+
+		r = io.MultiReader(strings.NewReader(line), br)
+	}
+
+	rv := reflect.ValueOf(value)
+	isStruct := rv.Kind() == reflect.Pointer && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct
+
+	maxMemory := mt.MaxMemory
+	if maxMemory <= 0 {
+		maxMemory = DefaultMultipartMaxMemory
+	}
+
+	form, err := multipart.NewReader(r, boundary).ReadForm(maxMemory)
+	if err != nil {
+		return err
+	}
+
+	switch t := value.(type) {
+	case *multipart.Form:
+		*t = *form
+		return nil
+	case *url.Values:
+		*t = form.Value
+		return form.RemoveAll()
+	case *map[string][]string:
+		*t = form.Value
+		return form.RemoveAll()
+	}
+
+	if !isStruct {
+		_ = form.RemoveAll()
+		return NewUnsupportedTypeError(Multipart, value)
+	}
+
+	if err = decodeForm(normalizeForm(form.Value), "", rv.Elem()); err != nil {
+		_ = form.RemoveAll()
+		return err
+	}
+
+	if decodeMultipartFiles(form, rv.Elem()) {
+		return nil
+	}
+
+	stored := multipartStored(form)
+	if err = form.RemoveAll(); err != nil {
+		return err
+	}
+
+	if stored && multipartHasFiles(rv.Elem().Type()) {
+		return ErrMultipartMaxMemory
+	}
+
+	return nil
+}
+
+// Boundary returns the boundary of the body.
+func (mb *MultipartBody) Boundary() string {
+	return mb.boundary
+}
+
+// ContentType returns the content type of the body including its boundary, e.g. for the Content-Type header.
+func (mb *MultipartBody) ContentType() string {
+	return Names[Multipart] + "; boundary=" + mb.boundary
+}
+
+// multipartParts splits the given value into form fields and named file parts.
+func multipartParts(value any) (url.Values, map[string][]MultipartFile, error) {
+	values := make(url.Values)
+	files := make(map[string][]MultipartFile)
+
+	switch t := value.(type) {
+	case url.Values:
+		return t, files, nil
+	case map[string][]string:
+		return t, files, nil
+	case map[string]string:
+		for key, value := range t {
+			values.Set(key, value)
+		}
+
+		return values, files, nil
+	case map[string]any:
+		for key, value := range t {
+			if err := addMultipartPart(values, files, key, reflect.ValueOf(value)); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		return values, files, nil
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(value))
+	if rv.Kind() != reflect.Struct {
+		return nil, nil, NewUnsupportedTypeError(Multipart, value)
+	}
+
+	if err := addMultipartStruct(values, files, rv); err != nil {
+		return nil, nil, err
+	}
+
+	return values, files, nil
+}
+
+// addMultipartStruct adds the fields of the struct as file parts or form fields, flattening the untagged embedded
+// structs as FormType does.
+func addMultipartStruct(values url.Values, files map[string][]MultipartFile, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if isEmbeddedForm(field) {
+			if err := addMultipartStruct(values, files, v.Field(i)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		name, omitempty := formField(field)
+		if name == "" || (omitempty && v.Field(i).IsZero()) {
+			continue
+		}
+
+		if err := addMultipartPart(values, files, name, v.Field(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addMultipartPart adds the value as file parts when it holds files, or as form fields otherwise.
+func addMultipartPart(values url.Values, files map[string][]MultipartFile, name string, v reflect.Value) error {
+	for v.Kind() == reflect.Interface || (v.Kind() == reflect.Pointer && v.Type().Elem() == multipartFileType) {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	switch {
+	case !v.IsValid():
+		return nil
+	case v.Type() == multipartFileType:
+		files[name] = append(files[name], v.Interface().(MultipartFile))
+		return nil
+	case v.Kind() == reflect.Slice && v.Type().Elem() == multipartFileType:
+		files[name] = append(files[name], v.Interface().([]MultipartFile)...)
+		return nil
+	}
+
+	return encodeFormValue(values, name, v)
+}
+
+// writeMultipart writes the form fields, then the file parts, in the order of their names and closes the writer.
+func writeMultipart(w *multipart.Writer, values url.Values, files map[string][]MultipartFile) error {
+	for _, name := range sortedKeys(values) {
+		for _, value := range values[name] {
+			if err := w.WriteField(name, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(files) {
+		for _, file := range files[name] {
+			contentType := file.ContentType
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+
+			header := make(textproto.MIMEHeader)
+			header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+				multipartQuoteReplacer.Replace(name), multipartQuoteReplacer.Replace(file.Filename)))
+			header.Set("Content-Type", contentType)
+
+			part, err := w.CreatePart(header)
+			if err != nil {
+				return err
+			}
+
+			if file.Reader != nil {
+				if _, err = io.Copy(part, file.Reader); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return w.Close()
+}
+
+// decodeMultipartFiles stores the file parts in the *multipart.FileHeader and []*multipart.FileHeader fields, and the
+// form in the *multipart.Form fields, of the given struct, flattening the untagged embedded structs. It reports
+// whether the form is kept by a *multipart.Form field.
+func decodeMultipartFiles(form *multipart.Form, v reflect.Value) bool {
+	kept := false
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if isEmbeddedForm(field) {
+			kept = decodeMultipartFiles(form, v.Field(i)) || kept
+			continue
+		}
+
+		if field.IsExported() && field.Type == multipartFormType {
+			kept = true
+			v.Field(i).Set(reflect.ValueOf(form))
+			continue
+		}
+
+		name, _ := formField(field)
+		if name == "" {
+			continue
+		}
+
+		switch field.Type {
+		case multipartFileHeaderType:
+			if headers := form.File[name]; len(headers) > 0 {
+				v.Field(i).Set(reflect.ValueOf(headers[0]))
+			}
+		case multipartFileHeadersType:
+			if headers := form.File[name]; len(headers) > 0 {
+				v.Field(i).Set(reflect.ValueOf(headers))
+			}
+		}
+	}
+
+	return kept
+}
+
+// multipartHasFiles reports whether the struct type, including its untagged embedded structs, has file fields.
+func multipartHasFiles(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if isEmbeddedForm(field) {
+			if multipartHasFiles(field.Type) {
+				return true
+			}
+			continue
+		}
+
+		if name, _ := formField(field); name != "" &&
+			(field.Type == multipartFileHeaderType || field.Type == multipartFileHeadersType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// multipartStored reports whether file parts of the form are stored in temporary files.
+func multipartStored(form *multipart.Form) bool {
+	for _, headers := range form.File {
+		for _, header := range headers {
+			f, err := header.Open()
+			if err != nil {
+				return true
+			}
+
+			_, stored := f.(*os.File)
+			_ = f.Close()
+			if stored {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// sortedKeys returns the sorted keys of the given map.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
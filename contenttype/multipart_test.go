@@ -0,0 +1,120 @@
+package contenttype_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"os"
+	"testing"
+
+	"github.com/leliuga/data/contenttype"
+)
+
+type MultipartBase struct {
+	ID int `form:"id"`
+}
+
+type MultipartUpload struct {
+	MultipartBase
+	Name string                `form:"name"`
+	File *multipart.FileHeader `form:"file"`
+}
+
+type MultipartUploadForm struct {
+	MultipartUpload
+	Form *multipart.Form
+}
+
+func marshalMultipart(t *testing.T, mt *contenttype.MultipartType, value any) io.Reader {
+	t.Helper()
+
+	r, err := mt.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	return r
+}
+
+func TestMultipartEmbedded(t *testing.T) {
+	mt := &contenttype.MultipartType{}
+	in := struct {
+		MultipartBase
+		Name string `form:"name"`
+	}{MultipartBase{ID: 7}, "a"}
+
+	var out MultipartUpload
+	if err := mt.Unmarshal(marshalMultipart(t, mt, in), &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if out.ID != 7 || out.Name != "a" {
+		t.Errorf("Unmarshal() = %+v, want ID 7 and Name %q", out, "a")
+	}
+}
+
+func TestMultipartTemporaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+
+	mt := &contenttype.MultipartType{MaxMemory: 1}
+	content := bytes.Repeat([]byte{'x'}, 1<<20)
+	in := func() map[string]any {
+		return map[string]any{
+			"name": "a",
+			"file": contenttype.MultipartFile{Filename: "a.bin", Reader: bytes.NewReader(content)},
+		}
+	}
+
+	t.Run("FileFields", func(t *testing.T) {
+		var out MultipartUpload
+		if err := mt.Unmarshal(marshalMultipart(t, mt, in()), &out); !errors.Is(err, contenttype.ErrMultipartMaxMemory) {
+			t.Fatalf("Unmarshal() error = %v, want %v", err, contenttype.ErrMultipartMaxMemory)
+		}
+		assertEmptyDir(t, dir)
+	})
+
+	t.Run("FileFieldsInMemory", func(t *testing.T) {
+		mt := &contenttype.MultipartType{MaxMemory: 2 << 20}
+
+		var out MultipartUpload
+		if err := mt.Unmarshal(marshalMultipart(t, mt, in()), &out); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+
+		if out.File == nil || out.File.Size != int64(len(content)) {
+			t.Fatalf("Unmarshal() File = %+v, want %d bytes", out.File, len(content))
+		}
+		assertEmptyDir(t, dir)
+	})
+
+	t.Run("FormField", func(t *testing.T) {
+		var out MultipartUploadForm
+		if err := mt.Unmarshal(marshalMultipart(t, mt, in()), &out); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+
+		if out.Form == nil || out.File == nil {
+			t.Fatalf("Unmarshal() = %+v, want the form and the file", out)
+		}
+
+		if err := out.Form.RemoveAll(); err != nil {
+			t.Fatalf("RemoveAll() error = %v", err)
+		}
+		assertEmptyDir(t, dir)
+	})
+}
+
+func assertEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+
+	if len(entries) > 0 {
+		t.Errorf("%s holds %d files, want none", dir, len(entries))
+	}
+}
@@ -0,0 +1,84 @@
+package contenttype
+
+import (
+	"errors"
+	"io"
+
+	"github.com/goccy/go-json"
+)
+
+// Marshal returns a reader for the given records, encoded lazily as the reader is consumed, one json record per line.
+// The value must be a slice or an array, a channel read until it is closed, or an iterator func(yield func(T) bool).
+// The encoding stops at the first error, which is returned by the reader. Closing the reader stops the encoding.
+func (nt *NdjsonType) Marshal(value any) (io.Reader, error) {
+	if _, err := records(Ndjson, value); err != nil {
+		return nil, err
+	}
+
+	return streamMarshal(nt.Encode, value), nil
+}
+
+// Encode writes the given records to the writer, one json record per line.
+// The value must be a slice or an array, a channel read until it is closed, or an iterator func(yield func(T) bool).
+func (nt *NdjsonType) Encode(w io.Writer, value any) error {
+	rv, err := records(Ndjson, value)
+	if err != nil {
+		return err
+	}
+
+	return encodeRecords(rv, NewNdjsonEncoder(w).Encode)
+}
+
+// Unmarshal parses the given reader and stores the records in the value.
+// The value must be a pointer to a slice, receiving all the records, a channel, receiving the records one at a time
+// and closed at the end, or a callback func(T) bool, called for each record until it returns false.
+func (nt *NdjsonType) Unmarshal(r io.Reader, value any) error {
+	return decodeRecords(Ndjson, value, NewNdjsonDecoder(r).Decode)
+}
+
+// NewNdjsonEncoder creates a new encoder writing to the given writer.
+func NewNdjsonEncoder(w io.Writer) *NdjsonEncoder {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+	return &NdjsonEncoder{encoder: encoder}
+}
+
+// Encode writes the record followed by a newline.
+func (e *NdjsonEncoder) Encode(record any) error {
+	return e.encoder.Encode(record)
+}
+
+// NewNdjsonDecoder creates a new decoder reading from the given reader.
+func NewNdjsonDecoder(r io.Reader) *NdjsonDecoder {
+	return &NdjsonDecoder{decoder: json.NewDecoder(r)}
+}
+
+// Decode reads the next record and stores it in the value pointed to by record, io.EOF is returned at the end.
+func (d *NdjsonDecoder) Decode(record any) error {
+	return d.decoder.Decode(record)
+}
+
+// More returns whether there is another record to decode.
+func (d *NdjsonDecoder) More() bool {
+	return d.decoder.More()
+}
+
+// EachNdjson decodes the records of the given reader one at a time and calls fn for each until it returns false.
+func EachNdjson[T any](r io.Reader, fn func(T) bool) error {
+	decoder := NewNdjsonDecoder(r)
+	for {
+		var record T
+		if err := decoder.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		if !fn(record) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package contenttype
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/leliuga/data/schema/http"
+)
+
+const identity = "identity"
+
+var (
+	// ErrNotAcceptable is returned when none of the offered values is acceptable to the client.
+	ErrNotAcceptable = http.NewError(http.StatusNotAcceptable, "not acceptable")
+)
+
+type (
+	// acceptRange is a range of an Accept, Accept-Charset or Accept-Encoding header value.
+	acceptRange struct {
+		value  string
+		params int
+		q      float64
+	}
+)
+
+// Negotiate returns the offered content type best matching the Accept header value according to RFC 9110,
+// honoring quality values, wildcards, e.g. "*/*" and "application/*", and the specificity of the ranges.
+// Content types are offered in order of server preference, all content types of Names if none.
+// The first offered content type is returned for an empty Accept header value.
+func Negotiate(accept string, offered ...ContentType) (ContentType, error) {
+	if len(offered) == 0 {
+		offered = make([]ContentType, 0, len(Names))
+		for ct := range Names {
+			offered = append(offered, ct)
+		}
+
+		sort.Slice(offered, func(i, j int) bool { return offered[i] < offered[j] })
+	}
+
+	index := negotiate(accept, len(offered), func(ar acceptRange, i int) int {
+		best := mediaRangeSpecificity(ar, Names[offered[i]])
+
+		// an alias only matches its exact range, so a wildcard never selects a type by a name the client did not accept
+		for _, alias := range aliases[offered[i]] {
+			if ar.value == alias {
+				if specificity := mediaRangeSpecificity(ar, alias); specificity > best {
+					best = specificity
+				}
+			}
+		}
+
+		return best
+	}, nil)
+	if index < 0 {
+		return Invalid, ErrNotAcceptable
+	}
+
+	return offered[index], nil
+}
+
+// NegotiateCharset returns the offered charset best matching the Accept-Charset header value.
+// The first offered charset is returned for an empty Accept-Charset header value.
+func NegotiateCharset(accept string, offered ...string) (string, error) {
+	index := negotiate(accept, len(offered), func(ar acceptRange, i int) int {
+		return tokenRangeSpecificity(ar, offered[i])
+	}, nil)
+	if index < 0 {
+		return "", ErrNotAcceptable
+	}
+
+	return offered[index], nil
+}
+
+// NegotiateEncoding returns the offered content coding best matching the Accept-Encoding header value.
+// The "identity" coding is acceptable unless excluded, e.g. by "identity;q=0" or "*;q=0".
+// The "identity" coding is preferred for an empty Accept-Encoding header value when offered, see RFC 9110 section
+// 12.5.3, otherwise the first offered content coding is returned.
+func NegotiateEncoding(accept string, offered ...string) (string, error) {
+	if len(parseAccept(accept)) == 0 {
+		for _, o := range offered {
+			if strings.EqualFold(o, identity) {
+				return o, nil
+			}
+		}
+	}
+
+	index := negotiate(accept, len(offered), func(ar acceptRange, i int) int {
+		return tokenRangeSpecificity(ar, offered[i])
+	}, func(i int) bool {
+		return strings.EqualFold(offered[i], identity)
+	})
+	if index < 0 {
+		return "", ErrNotAcceptable
+	}
+
+	return offered[index], nil
+}
+
+// negotiate returns the index of the offered value with the highest quality, then the most specific matching
+// range, then the lowest index, or -1 if none is acceptable. The match function returns the specificity of the
+// range matching the offered value at the index or -1. The optional implicit function reports whether the
+// offered value at the index is acceptable with the lowest quality when no range matches it.
+func negotiate(accept string, count int, match func(ar acceptRange, i int) int, implicit func(i int) bool) int {
+	if count == 0 {
+		return -1
+	}
+
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return 0
+	}
+
+	index, quality, specificity := -1, 0.0, -1
+	for i := 0; i < count; i++ {
+		q, s := -1.0, -1
+		for _, ar := range ranges {
+			if rs := match(ar, i); rs > s {
+				q, s = ar.q, rs
+			}
+		}
+
+		if s < 0 && implicit != nil && implicit(i) {
+			q, s = 0.001, 0
+		}
+
+		if q > quality || (q == quality && q > 0 && s > specificity) {
+			index, quality, specificity = i, q, s
+		}
+	}
+
+	return index
+}
+
+// mediaRangeSpecificity returns the specificity of the media range matching the media type or -1.
+func mediaRangeSpecificity(ar acceptRange, mime string) int {
+	typ, subtype, _ := strings.Cut(mime, "/")
+	rangeType, rangeSubtype, _ := strings.Cut(ar.value, "/")
+
+	switch {
+	case rangeType == "*" && rangeSubtype == "*":
+		return 1
+	case rangeType == typ && rangeSubtype == "*":
+		return 2
+	case rangeType == typ && rangeSubtype == subtype:
+		return 3 + ar.params
+	}
+
+	return -1
+}
+
+// tokenRangeSpecificity returns the specificity of the charset or content coding range matching the value or -1.
+func tokenRangeSpecificity(ar acceptRange, value string) int {
+	switch {
+	case ar.value == "*":
+		return 1
+	case strings.EqualFold(ar.value, value):
+		return 2
+	}
+
+	return -1
+}
+
+// parseAccept parses the comma separated ranges of an Accept, Accept-Charset or Accept-Encoding header value.
+// Ranges with an invalid quality value are ignored.
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		ar := acceptRange{
+			value: strings.ToLower(strings.TrimSpace(fields[0])),
+			q:     1,
+		}
+		if ar.value == "" {
+			continue
+		}
+
+		valid := true
+		for _, param := range fields[1:] {
+			name, value, _ := strings.Cut(param, "=")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name != "q" {
+				ar.params++
+				continue
+			}
+
+			q, err := strconv.ParseFloat(strings.Trim(strings.TrimSpace(value), `"`), 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+
+			ar.q = q
+		}
+
+		if valid {
+			ranges = append(ranges, ar)
+		}
+	}
+
+	return ranges
+}
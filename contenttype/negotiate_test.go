@@ -0,0 +1,94 @@
+package contenttype_test
+
+import (
+	"testing"
+
+	"github.com/leliuga/data/contenttype"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name    string
+		accept  string
+		offered []contenttype.ContentType
+		want    contenttype.ContentType
+	}{
+		{"empty", "", []contenttype.ContentType{contenttype.Json, contenttype.Xml}, contenttype.Json},
+		{"exact", "application/xml", []contenttype.ContentType{contenttype.Json, contenttype.Xml}, contenttype.Xml},
+		{"quality", "application/json;q=0.5, application/xml", []contenttype.ContentType{contenttype.Json, contenttype.Xml}, contenttype.Xml},
+		{"server preference", "application/json, application/xml", []contenttype.ContentType{contenttype.Xml, contenttype.Json}, contenttype.Xml},
+		{"excluded", "*/*, application/json;q=0", []contenttype.ContentType{contenttype.Json, contenttype.Xml}, contenttype.Xml},
+		{"specificity", "text/*;q=0.5, text/html", []contenttype.ContentType{contenttype.Text, contenttype.Html}, contenttype.Html},
+		{"specific range quality", "text/*, text/plain;q=0.2", []contenttype.ContentType{contenttype.Text, contenttype.Html}, contenttype.Html},
+		{"parameters", "text/plain;q=0.2, text/plain;format=flowed;q=0.8, */*;q=0.5", []contenttype.ContentType{contenttype.Json, contenttype.Text}, contenttype.Text},
+		{"type wildcard", "text/*", []contenttype.ContentType{contenttype.Json, contenttype.Html}, contenttype.Html},
+		{"any", "*/*", []contenttype.ContentType{contenttype.Yaml, contenttype.Json}, contenttype.Yaml},
+		{"alias", "text/json", []contenttype.ContentType{contenttype.Html, contenttype.Json}, contenttype.Json},
+		{"alias with wildcard", "text/*", []contenttype.ContentType{contenttype.Json, contenttype.Yaml, contenttype.Csv}, contenttype.Csv},
+		{"all offered", "application/x-ndjson", nil, contenttype.Ndjson},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := contenttype.Negotiate(tt.accept, tt.offered...)
+			if err != nil {
+				t.Fatalf("Negotiate(%q) error = %v", tt.accept, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Negotiate(%q) = %v, want %v", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNegotiateNotAcceptable(t *testing.T) {
+	tests := []struct {
+		name    string
+		accept  string
+		offered []contenttype.ContentType
+	}{
+		{"no match", "image/png", []contenttype.ContentType{contenttype.Json}},
+		{"excluded", "application/json;q=0", []contenttype.ContentType{contenttype.Json}},
+		{"alias with wildcard", "text/*", []contenttype.ContentType{contenttype.Json, contenttype.Yaml}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := contenttype.Negotiate(tt.accept, tt.offered...); err != contenttype.ErrNotAcceptable {
+				t.Errorf("Negotiate(%q) = %v, %v, want %v", tt.accept, got, err, contenttype.ErrNotAcceptable)
+			}
+		})
+	}
+}
+
+func TestNegotiateCharset(t *testing.T) {
+	offered := []string{"utf-8", "iso-8859-1", "utf-16"}
+	tests := []struct {
+		name    string
+		accept  string
+		want    string
+		wantErr error
+	}{
+		{"empty", "", "utf-8", nil},
+		{"exact", "iso-8859-1", "iso-8859-1", nil},
+		{"case insensitive", "ISO-8859-1", "iso-8859-1", nil},
+		{"quality", "utf-8;q=0.5, utf-16", "utf-16", nil},
+		{"server preference", "utf-16, iso-8859-1", "iso-8859-1", nil},
+		{"wildcard", "*", "utf-8", nil},
+		{"specificity", "*;q=0.5, utf-16", "utf-16", nil},
+		{"excluded", "*, utf-8;q=0", "iso-8859-1", nil},
+		{"invalid quality", "utf-8;q=2, utf-16", "utf-16", nil},
+		{"no match", "koi8-r", "", contenttype.ErrNotAcceptable},
+		{"all excluded", "*;q=0", "", contenttype.ErrNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := contenttype.NegotiateCharset(tt.accept, offered...)
+			if err != tt.wantErr || got != tt.want {
+				t.Errorf("NegotiateCharset(%q) = %q, %v, want %q, %v", tt.accept, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
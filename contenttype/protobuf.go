@@ -0,0 +1,38 @@
+package contenttype
+
+import (
+	"bytes"
+	"io"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Marshal returns a reader for the given value, which must be a proto.Message.
+func (pt *ProtobufType) Marshal(value any) (io.Reader, error) {
+	message, ok := value.(proto.Message)
+	if !ok {
+		return nil, NewUnsupportedTypeError(Protobuf, value)
+	}
+
+	b, err := proto.MarshalOptions{Deterministic: pt.Deterministic}.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(b), nil
+}
+
+// Unmarshal parses the given reader and stores the result in the value, which must be a proto.Message.
+func (pt *ProtobufType) Unmarshal(r io.Reader, value any) error {
+	message, ok := value.(proto.Message)
+	if !ok {
+		return NewUnsupportedTypeError(Protobuf, value)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(b, message)
+}
@@ -0,0 +1,117 @@
+package contenttype
+
+import (
+	"errors"
+	"io"
+	"reflect"
+)
+
+// records returns the reflect value of the records to encode, a slice, an array, a channel or an iterator.
+func records(ct ContentType, value any) (reflect.Value, error) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Chan:
+	case reflect.Func:
+		if !isIterator(rv.Type()) {
+			return rv, NewUnsupportedTypeError(ct, value)
+		}
+	default:
+		return rv, NewUnsupportedTypeError(ct, value)
+	}
+
+	return rv, nil
+}
+
+// encodeRecords encodes the records of the slice, array, channel or iterator until the first error.
+func encodeRecords(rv reflect.Value, encode func(record any) error) error {
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := encode(rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	case reflect.Chan:
+		for {
+			record, ok := rv.Recv()
+			if !ok {
+				break
+			}
+
+			if err := encode(record.Interface()); err != nil {
+				return err
+			}
+		}
+	case reflect.Func:
+		var err error
+		yield := reflect.MakeFunc(rv.Type().In(0), func(args []reflect.Value) []reflect.Value {
+			err = encode(args[0].Interface())
+			return []reflect.Value{reflect.ValueOf(err == nil)}
+		})
+
+		rv.Call([]reflect.Value{yield})
+
+		return err
+	}
+
+	return nil
+}
+
+// decodeRecords decodes the records until io.EOF and stores them in the value, a pointer to a slice, receiving
+// all the records, a channel, receiving the records one at a time and closed at the end, or a callback
+// func(T) bool, called for each record until it returns false.
+func decodeRecords(ct ContentType, value any, decode func(record any) error) error {
+	rv := reflect.ValueOf(value)
+
+	switch {
+	case rv.Kind() == reflect.Pointer && !rv.IsNil() && rv.Elem().Kind() == reflect.Slice:
+		slice := rv.Elem()
+		slice.SetLen(0)
+
+		return eachRecord(decode, slice.Type().Elem(), func(record reflect.Value) bool {
+			slice.Set(reflect.Append(slice, record))
+			return true
+		})
+	case rv.Kind() == reflect.Chan && rv.Type().ChanDir()&reflect.SendDir != 0:
+		defer rv.Close()
+
+		return eachRecord(decode, rv.Type().Elem(), func(record reflect.Value) bool {
+			rv.Send(record)
+			return true
+		})
+	case rv.Kind() == reflect.Func && isCallback(rv.Type()):
+		return eachRecord(decode, rv.Type().In(0), func(record reflect.Value) bool {
+			return rv.Call([]reflect.Value{record})[0].Bool()
+		})
+	}
+
+	return NewUnsupportedTypeError(ct, value)
+}
+
+// eachRecord decodes the records as the given type and calls fn for each until it returns false.
+func eachRecord(decode func(record any) error, t reflect.Type, fn func(reflect.Value) bool) error {
+	for {
+		record := reflect.New(t)
+		if err := decode(record.Interface()); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		if !fn(record.Elem()) {
+			return nil
+		}
+	}
+}
+
+// isIterator returns whether the type is an iterator func(yield func(T) bool).
+func isIterator(t reflect.Type) bool {
+	return t.NumIn() == 1 && t.NumOut() == 0 && isCallback(t.In(0))
+}
+
+// isCallback returns whether the type is a callback func(T) bool.
+func isCallback(t reflect.Type) bool {
+	return t.Kind() == reflect.Func && t.NumIn() == 1 && t.NumOut() == 1 && t.Out(0).Kind() == reflect.Bool
+}
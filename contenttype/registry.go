@@ -0,0 +1,111 @@
+package contenttype
+
+import (
+	"sort"
+	"strings"
+)
+
+var (
+	// DefaultRegistry is the registry preloaded with the content types of Names and Set, and their common aliases.
+	DefaultRegistry = newDefaultRegistry()
+
+	// aliases is a map of content type values to the other MIME types they are known by.
+	aliases = map[ContentType][]string{
+		Json:    {"text/json"},
+		MsgPack: {"application/x-msgpack", "application/vnd.msgpack"},
+		Ndjson:  {"application/jsonl", "application/x-jsonlines"},
+		Yaml:    {"application/x-yaml", "text/yaml", "text/x-yaml"},
+	}
+)
+
+// NewRegistry creates a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		codecs: make(map[string]IContentType),
+	}
+}
+
+// Register registers the codec for the MIME type and its aliases, replacing any codec registered for them.
+// MIME types are matched case-insensitively and without their parameters.
+func (r *Registry) Register(mime string, codec IContentType, aliases ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.codecs[normalizeMime(mime)] = codec
+	for _, alias := range aliases {
+		r.codecs[normalizeMime(alias)] = codec
+	}
+}
+
+// Unregister removes the codec registered for the MIME type.
+func (r *Registry) Unregister(mime string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.codecs, normalizeMime(mime))
+}
+
+// Lookup returns the codec registered for the MIME type, e.g. "application/json; charset=utf-8".
+// An unregistered MIME type with a structured syntax suffix falls back to the codec of the suffix, e.g.
+// "application/vnd.acme+json" to "application/json" and "application/atom+xml" to "application/xml".
+func (r *Registry) Lookup(mime string) (IContentType, bool) {
+	mime = normalizeMime(mime)
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if codec, ok := r.codecs[mime]; ok {
+		return codec, true
+	}
+
+	if i := strings.LastIndexByte(mime, '+'); i > -1 && strings.Contains(mime[:i], "/") {
+		codec, ok := r.codecs["application/"+mime[i+1:]]
+		return codec, ok
+	}
+
+	return nil, false
+}
+
+// Mimes returns the sorted registered MIME types.
+func (r *Registry) Mimes() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	mimes := make([]string, 0, len(r.codecs))
+	for mime := range r.codecs {
+		mimes = append(mimes, mime)
+	}
+
+	sort.Strings(mimes)
+
+	return mimes
+}
+
+// Register registers the codec for the MIME type and its aliases in the DefaultRegistry.
+func Register(mime string, codec IContentType, aliases ...string) {
+	DefaultRegistry.Register(mime, codec, aliases...)
+}
+
+// Lookup returns the codec registered for the MIME type in the DefaultRegistry.
+func Lookup(mime string) (IContentType, bool) {
+	return DefaultRegistry.Lookup(mime)
+}
+
+// newDefaultRegistry creates the registry of the content types of Names and Set.
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for ct, name := range Names {
+		if codec, ok := Set[ct]; ok {
+			r.Register(name, codec, aliases[ct]...)
+		}
+	}
+
+	return r
+}
+
+// normalizeMime returns the lower case MIME type without its parameters.
+func normalizeMime(mime string) string {
+	mime, _, _ = strings.Cut(mime, ";")
+
+	return strings.ToLower(strings.TrimSpace(mime))
+}
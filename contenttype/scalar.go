@@ -0,0 +1,131 @@
+package contenttype
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/leliuga/data/constants"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isScalar returns whether the type is encoded as a single text value.
+func isScalar(t reflect.Type) bool {
+	if t == timeType || t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	case reflect.Struct, reflect.Array, reflect.Map, reflect.Pointer, reflect.Interface:
+		return false
+	}
+
+	return true
+}
+
+// formatScalar formats the given scalar as text, time.Time with constants.DefaultDateTimeFormat.
+func formatScalar(ct ContentType, v reflect.Value) (string, error) {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(constants.DefaultDateTimeFormat), nil
+	}
+
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice:
+		return string(v.Bytes()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+
+	return "", NewUnsupportedTypeError(ct, v.Interface())
+}
+
+// parseScalar parses the text into the given scalar, time.Time with constants.DefaultDateTimeFormat or RFC 3339.
+func parseScalar(ct ContentType, v reflect.Value, value string) error {
+	if v.Type() == timeType {
+		t, err := time.Parse(constants.DefaultDateTimeFormat, value)
+		if err != nil {
+			if t, err = time.Parse(time.RFC3339, value); err != nil {
+				return err
+			}
+		}
+
+		v.Set(reflect.ValueOf(t))
+
+		return nil
+	}
+
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(value))
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Slice:
+		v.SetBytes([]byte(value))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(n)
+	default:
+		return NewUnsupportedTypeError(ct, v.Interface())
+	}
+
+	return nil
+}
+
+// indirectType returns the type pointed to by the given pointer types.
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
@@ -0,0 +1,65 @@
+package contenttype
+
+import (
+	"io"
+)
+
+// Encode writes the encoding of the value by the codec to the writer, directly for an IStreamContentType.
+func Encode(codec IContentType, w io.Writer, value any) error {
+	if sc, ok := codec.(IStreamContentType); ok {
+		return sc.Encode(w, value)
+	}
+
+	r, err := codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, r)
+
+	return err
+}
+
+// startMarshal returns a reader of the value encoded by the encode function as the reader is consumed, once the
+// encoding has written its first bytes. An error occurring before them is returned, a later one by the reader.
+// The reader must be read until EOF or closed to release the encoding goroutine.
+func startMarshal(encode func(io.Writer, any) error, value any) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	sw := &startWriter{w: pw, started: make(chan struct{})}
+	done := make(chan error, 1)
+	go func() {
+		err := encode(sw, value)
+		pw.CloseWithError(err)
+		done <- err
+	}()
+
+	select {
+	case <-sw.started:
+	case err := <-done:
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pr, nil
+}
+
+// Write signals the first write and writes p to the underlying writer.
+func (sw *startWriter) Write(p []byte) (int, error) {
+	sw.once.Do(func() {
+		close(sw.started)
+	})
+
+	return sw.w.Write(p)
+}
+
+// streamMarshal returns a reader of the value encoded lazily by the encode function as the reader is consumed.
+// The reader must be read until EOF or closed to release the encoding goroutine.
+func streamMarshal(encode func(io.Writer, any) error, value any) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(encode(pw, value))
+	}()
+
+	return pr
+}
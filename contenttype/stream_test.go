@@ -0,0 +1,73 @@
+package contenttype_test
+
+import (
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leliuga/data/contenttype"
+)
+
+var streamCodecs = map[string]contenttype.IContentType{
+	"json":    &contenttype.JsonType{},
+	"yaml":    &contenttype.YamlType{},
+	"msgpack": &contenttype.MsgPackType{},
+	"cbor":    &contenttype.CborType{},
+}
+
+func TestStreamMarshalError(t *testing.T) {
+	for name, codec := range streamCodecs {
+		codec := codec
+		t.Run(name, func(t *testing.T) {
+			if r, err := codec.Marshal(make(chan int)); err == nil {
+				_, _ = io.Copy(io.Discard, r)
+				t.Fatalf("Marshal() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestStreamMarshalClose(t *testing.T) {
+	value := make([]string, 1<<14)
+	for i := range value {
+		value[i] = strings.Repeat("x", 64)
+	}
+
+	for name, codec := range streamCodecs {
+		codec := codec
+		t.Run(name, func(t *testing.T) {
+			goroutines := runtime.NumGoroutine()
+
+			r, err := codec.Marshal(value)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			rc, ok := r.(io.ReadCloser)
+			if !ok {
+				t.Fatalf("Marshal() = %T, want an io.ReadCloser", r)
+			}
+
+			if _, err = rc.Read(make([]byte, 16)); err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+
+			if err = rc.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			if _, err = rc.Read(make([]byte, 16)); err != io.ErrClosedPipe {
+				t.Fatalf("Read() error = %v, want %v", err, io.ErrClosedPipe)
+			}
+
+			for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > goroutines; {
+				if time.Now().After(deadline) {
+					t.Fatalf("NumGoroutine() = %d, want %d", runtime.NumGoroutine(), goroutines)
+				}
+				time.Sleep(time.Millisecond)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package contenttype
 
 import (
 	"bytes"
+	"encoding"
 	"fmt"
 	"io"
 )
@@ -12,13 +13,30 @@ func (tt *TextType) Marshal(value any) (io.Reader, error) {
 }
 
 // Unmarshal parses the given reader and stores the result in the value pointed to by value.
+// The value must be a *string, a *[]byte, an encoding.TextUnmarshaler or an *any.
 func (tt *TextType) Unmarshal(r io.Reader, value any) error {
+	return unmarshalText(Text, r, value)
+}
+
+// unmarshalText reads the given reader and stores the text in the value pointed to by value.
+func unmarshalText(ct ContentType, r io.Reader, value any) error {
 	b, err := io.ReadAll(r)
 	if err != nil {
 		return err
 	}
 
-	value = string(b)
+	switch v := value.(type) {
+	case *string:
+		*v = string(b)
+	case *[]byte:
+		*v = b
+	case encoding.TextUnmarshaler:
+		return v.UnmarshalText(b)
+	case *any:
+		*v = string(b)
+	default:
+		return NewUnsupportedTypeError(ct, value)
+	}
 
 	return nil
 }
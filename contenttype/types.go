@@ -1,31 +1,253 @@
 package contenttype
 
 import (
+	"bufio"
+	"html/template"
 	"io"
+	"io/fs"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
 )
 
 type (
+	// CborType is a cbor type, encoding with the core deterministic encoding of RFC 8949 so the output can be signed.
+	CborType struct{}
+
+	// CsvType is a csv type, also used for tab-separated values.
+	CsvType struct {
+		// Comma is the field delimiter. Optional. Default is ','.
+		Comma rune
+	}
+
+	// EncodedType is a content type wrapping a codec with a content coding, compressing on Marshal and
+	// decompressing on Unmarshal.
+	EncodedType struct {
+		// Codec is the wrapped content type.
+		Codec IContentType
+
+		// Encoding is the content coding of the body.
+		//
+		// Optional. Default is EncodingInvalid, failing with ErrEncodingInvalid
+		Encoding Encoding
+
+		// Level is the compression level of the Encoding, e.g. 1-9 for gzip, 0-11 for brotli and 1-22 for zstd.
+		//
+		// Optional. Default is 0, the default level of the Encoding
+		Level int
+	}
+
+	// Event is a server-sent event of a text/event-stream.
+	Event struct {
+		ID    string        `json:"id,omitempty" yaml:"ID,omitempty"`
+		Event string        `json:"event,omitempty" yaml:"Event,omitempty"` // empty means "message"
+		Data  string        `json:"data" yaml:"Data"`
+		Retry time.Duration `json:"retry,omitempty" yaml:"Retry,omitempty"` // reconnection time, 0 means unset
+	}
+
+	// EventStreamType is a text/event-stream type of server-sent events.
+	EventStreamType struct{}
+
+	// EventStreamEncoder writes server-sent events to a stream, flushing the writer after each one.
+	EventStreamEncoder struct {
+		w io.Writer
+	}
+
+	// EventStreamDecoder reads server-sent events from a stream, one at a time.
+	EventStreamDecoder struct {
+		scanner     *bufio.Scanner
+		lastEventID string
+		retry       time.Duration
+	}
+
 	// FormType is a form type.
 	FormType struct{}
 
-	// HtmlType is a html type.
-	HtmlType struct{}
+	// HtmlType is a html type rendering html/template templates from a file system. Without templates, values are
+	// written as is.
+	HtmlType struct {
+		// FS is the file system of the templates. Optional. Default is nil, no templates.
+		FS fs.FS
+
+		// Layouts are the glob patterns of the layouts and partials shared by every page, e.g. "layouts/*.html".
+		Layouts []string
+
+		// Pages are the glob patterns of the pages, each one named by its path. Optional. Default is "*.html".
+		Pages []string
+
+		// Layout is the name of the layout executed for a page, which defines the blocks of the layout.
+		// Optional. Default is empty, the page being executed.
+		Layout string
+
+		// Template is the name of the template rendering a value not wrapped in an HtmlView. Optional. Default is empty.
+		Template string
+
+		// Funcs are the functions available to the templates.
+		Funcs template.FuncMap
+
+		base  *template.Template
+		pages map[string]*template.Template
+		err   error
+		once  sync.Once
+	}
+
+	// HtmlView is a value rendered by HtmlType with the named template.
+	HtmlView struct {
+		Template string // name of the page or template
+		Layout   string // overrides the Layout of the HtmlType if not empty
+		Data     any
+	}
+
+	// DecodeError is returned when a codec can not decode a body, locating the failure when the codec reports it.
+	DecodeError struct {
+		ContentType ContentType
+		Path        string // e.g. "$.items[2].name", empty if unknown
+		Offset      int64  // byte offset of the failure in the body, -1 if unknown
+		Err         error
+	}
 
 	// JsonType is a json type.
-	JsonType struct{}
+	JsonType struct {
+		// DisallowUnknownFields rejects object keys not matching a field of the destination struct.
+		DisallowUnknownFields bool
+
+		// UseNumber decodes numbers into an interface as json.Number instead of float64, keeping large integers.
+		UseNumber bool
+
+		// MaxDepth is the maximum nesting depth of objects and arrays. Optional. Default is 0, no limit.
+		MaxDepth int
+
+		// MaxSize is the maximum size in bytes of a body. Optional. Default is 0, no limit.
+		MaxSize int64
+	}
+
+	// MediaType is a parsed media type, e.g. "application/vnd.api+json; charset=utf-8". The Type, Subtype,
+	// Suffix and parameter names are lower case.
+	MediaType struct {
+		Type    string            // e.g. "application"
+		Subtype string            // e.g. "vnd.api+json"
+		Suffix  string            // e.g. "json", the structured syntax suffix of the subtype if any
+		Params  map[string]string // e.g. {"charset": "utf-8"}
+	}
 
 	// MsgPackType is a msgpack type.
-	MsgPackType struct{}
+	MsgPackType struct {
+		// UseArrayEncodedStructs encodes structs as arrays of their field values instead of maps, decoded either way.
+		UseArrayEncodedStructs bool
+
+		// DisallowUnknownFields rejects map keys not matching a field of the destination struct.
+		DisallowUnknownFields bool
+
+		// MaxDepth is the maximum nesting depth of maps and arrays. Optional. Default is 0, no limit.
+		MaxDepth int
+
+		// MaxSize is the maximum size in bytes of a body. Optional. Default is 0, no limit.
+		MaxSize int64
+	}
+
+	// MultipartType is a multipart form-data type.
+	MultipartType struct {
+		// Boundary is the boundary used to encode and decode, a random one is generated when encoding and it is read
+		// from the first delimiter line when decoding if it is empty.
+		Boundary string
+
+		// MaxMemory is the number of bytes of the file parts kept in memory when decoding, the rest is stored in
+		// temporary files. 0 means DefaultMultipartMaxMemory.
+		MaxMemory int64
+	}
+
+	// startWriter is a writer closing started on its first write.
+	startWriter struct {
+		w       io.Writer
+		started chan struct{}
+		once    sync.Once
+	}
+
+	// nopWriteCloser is a writer with a no-op Close method.
+	nopWriteCloser struct {
+		io.Writer
+	}
+
+	// MultipartFile is a file part of a multipart form, its name is the struct field or the map key holding it.
+	MultipartFile struct {
+		Filename    string
+		ContentType string
+		Reader      io.Reader
+	}
+
+	// MultipartBody is the reader returned by MultipartType.Marshal, it exposes the boundary of the encoded body.
+	MultipartBody struct {
+		io.Reader
+		boundary string
+	}
+
+	// NdjsonType is a newline delimited json type, also known as JSON Lines.
+	NdjsonType struct{}
+
+	// NdjsonEncoder writes json records to a stream, one per line.
+	NdjsonEncoder struct {
+		encoder *json.Encoder
+	}
+
+	// NdjsonDecoder reads json records from a stream, one at a time.
+	NdjsonDecoder struct {
+		decoder *json.Decoder
+	}
+
+	// ProtobufType is a protocol buffers type, the values must be proto.Message.
+	ProtobufType struct {
+		// Deterministic orders the map entries when encoding, so equal messages are encoded to the same bytes.
+		Deterministic bool
+	}
+
+	// Registry is a registry of content types by MIME type, open to vendor types.
+	Registry struct {
+		codecs map[string]IContentType
+		mutex  sync.RWMutex
+	}
 
 	// TextType is a text type.
 	TextType struct{}
 
+	// XmlType is a xml type.
+	XmlType struct {
+		// Charset is the charset of the encoded documents, and of the decoded documents without an encoding declaration.
+		// Optional. Default is constants.DefaultCharset.
+		Charset string
+
+		// Indent is the indentation of the nested elements of the encoded documents, empty means no indentation.
+		Indent string
+	}
+
 	// YamlType is a yaml type.
-	YamlType struct{}
+	YamlType struct {
+		// Strict rejects unknown fields and duplicate keys.
+		Strict bool
+
+		// DisallowUnknownFields rejects mapping keys not matching a field of the destination struct.
+		DisallowUnknownFields bool
+
+		// MaxDepth is the maximum nesting depth of mappings and sequences. Optional. Default is 0, no limit.
+		MaxDepth int
+
+		// MaxSize is the maximum size in bytes of a body. Optional. Default is 0, no limit.
+		MaxSize int64
+	}
 
 	// ContentType is a content type.
 	ContentType uint8
 
+	// Encoding is a content coding, e.g. of the Content-Encoding header.
+	Encoding uint8
+
+	// UnsupportedTypeError is returned when a content type can not decode into or encode from a Go type.
+	UnsupportedTypeError struct {
+		ContentType ContentType
+		Type        reflect.Type
+	}
+
 	// IContentType is a content type interface.
 	IContentType interface {
 		// Marshal marshals the value to a reader.
@@ -34,4 +256,12 @@ type (
 		// Unmarshal unmarshals the reader to a value.
 		Unmarshal(io.Reader, any) error
 	}
+
+	// IStreamContentType is a content type interface encoding directly to a writer.
+	IStreamContentType interface {
+		IContentType
+
+		// Encode encodes the value to the writer.
+		Encode(io.Writer, any) error
+	}
 )
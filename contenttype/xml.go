@@ -0,0 +1,86 @@
+package contenttype
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/leliuga/data/constants"
+)
+
+const (
+	// xmlPrologSize is the number of bytes searched for the encoding of the XML declaration.
+	xmlPrologSize = 256
+)
+
+var (
+	// xmlEncodingPattern matches an XML declaration with an encoding, e.g. `<?xml version="1.0" encoding="UTF-8"?>`.
+	xmlEncodingPattern = regexp.MustCompile(`^\x{feff}?\s*<\?xml[^>]*\sencoding\s*=`)
+)
+
+// Marshal returns a reader for the given value.
+func (xt *XmlType) Marshal(value any) (io.Reader, error) {
+	buffer := bytes.NewBuffer(nil)
+	if err := xt.Encode(buffer, value); err != nil {
+		return nil, err
+	}
+
+	return buffer, nil
+}
+
+// Unmarshal parses the given reader and stores the result in the value pointed to by value.
+func (xt *XmlType) Unmarshal(r io.Reader, value any) error {
+	return xt.Decode(r, value)
+}
+
+// Encode writes the XML declaration and the given value to the writer, transcoded to the Charset.
+func (xt *XmlType) Encode(w io.Writer, value any) error {
+	charset := xt.Charset
+	if charset == "" {
+		charset = constants.DefaultCharset
+	}
+
+	cw, err := NewCharsetWriter(charset, w)
+	if err != nil {
+		return err
+	}
+
+	if _, err = fmt.Fprintf(cw, "<?xml version=\"1.0\" encoding=\"%s\"?>\n", charset); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(cw)
+	encoder.Indent("", xt.Indent)
+	if err = encoder.Encode(value); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// Decode reads the next XML element from the reader and stores it in the value pointed to by value.
+// A document in a non UTF-8 encoding, e.g. ISO-8859-1, is transcoded from the encoding of its declaration, or from
+// the Charset without one.
+func (xt *XmlType) Decode(r io.Reader, value any) error {
+	if xt.Charset != "" && !IsUTF8(xt.Charset) {
+		br := bufio.NewReader(r)
+		prolog, _ := br.Peek(xmlPrologSize)
+		r = br
+
+		if !xmlEncodingPattern.Match(prolog) {
+			cr, err := NewCharsetReader(xt.Charset, br)
+			if err != nil {
+				return err
+			}
+			r = cr
+		}
+	}
+
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = NewCharsetReader
+
+	return decoder.Decode(value)
+}
@@ -0,0 +1,37 @@
+package contenttype_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/leliuga/data/contenttype"
+)
+
+type XmlNote struct {
+	Body string `xml:"body"`
+}
+
+func TestXmlCharset(t *testing.T) {
+	tests := []struct {
+		name string
+		body []byte
+	}{
+		{"without declaration", []byte("<note><body>caf\xe9</body></note>")},
+		{"with declaration", []byte(`<?xml version="1.0" encoding="ISO-8859-1"?><note><body>caf` + "\xe9" + `</body></note>`)},
+		{"declaration overrides charset", []byte(`<?xml version="1.0" encoding="UTF-8"?><note><body>café</body></note>`)},
+	}
+
+	mt := contenttype.MustParseMediaType("application/xml; charset=ISO-8859-1")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var note XmlNote
+			if err := mt.Unmarshal(bytes.NewReader(tt.body), &note); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			if note.Body != "café" {
+				t.Fatalf("Unmarshal() body = %q, want %q", note.Body, "café")
+			}
+		})
+	}
+}
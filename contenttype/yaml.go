@@ -3,21 +3,133 @@ package contenttype
 import (
 	"bytes"
 	"io"
+	"regexp"
+	"strconv"
 
 	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
 )
 
-// Marshal returns a reader for the given value.
-func (yt *YamlType) Marshal(value any) (io.Reader, error) {
-	buffer := bytes.NewBuffer(nil)
-	if err := yaml.NewEncoder(buffer).Encode(value); err != nil {
-		return nil, err
+type (
+	// yamlPathVisitor is an ast visitor finding the path of the node at a position.
+	yamlPathVisitor struct {
+		line   int
+		column int
+		path   string
 	}
+)
+
+var (
+	// yamlPositionPattern matches the position prefix of the yaml decoder errors, e.g. "[3:1]".
+	yamlPositionPattern = regexp.MustCompile(`^\[(\d+):(\d+)\]`)
+)
 
-	return buffer, nil
+// Marshal returns a reader for the given value, encoded as the reader is consumed. An encoding error is returned
+// before the first byte is written, a later one by the reader. The reader is an io.ReadCloser, to be read until EOF
+// or closed to stop the encoding, and the value must not be mutated until then.
+func (yt *YamlType) Marshal(value any) (io.Reader, error) {
+	return startMarshal(yt.Encode, value)
+}
+
+// Encode writes the encoding of the given value to the writer.
+func (yt *YamlType) Encode(w io.Writer, value any) error {
+	return yaml.NewEncoder(w).Encode(value)
 }
 
 // Unmarshal parses the given reader and stores the result in the value pointed to by value.
+// A failure is returned as a DecodeError, locating unknown fields and mismatched types by their path.
 func (yt *YamlType) Unmarshal(r io.Reader, value any) error {
-	return yaml.NewDecoder(r).Decode(value)
+	lr := newLimitedReader(r, yt.MaxSize)
+	data, err := io.ReadAll(lr)
+	if err != nil {
+		return &DecodeError{ContentType: Yaml, Offset: lr.read, Err: err}
+	}
+
+	var file *ast.File
+	if yt.MaxDepth > 0 {
+		if file, err = parser.ParseBytes(data, 0); err != nil {
+			return newDecodeError(Yaml, err)
+		}
+
+		for _, doc := range file.Docs {
+			if yamlDepth(doc.Body) > yt.MaxDepth {
+				return &DecodeError{ContentType: Yaml, Offset: -1, Err: ErrMaxDepth}
+			}
+		}
+	}
+
+	var options []yaml.DecodeOption
+	if yt.Strict {
+		options = append(options, yaml.Strict())
+	}
+	if yt.DisallowUnknownFields {
+		options = append(options, yaml.DisallowUnknownField())
+	}
+
+	if err = yaml.NewDecoder(bytes.NewReader(data), options...).Decode(value); err != nil {
+		de := newDecodeError(Yaml, err)
+		if match := yamlPositionPattern.FindStringSubmatch(err.Error()); match != nil {
+			if file == nil {
+				file, _ = parser.ParseBytes(data, 0)
+			}
+
+			if file != nil {
+				v := &yamlPathVisitor{}
+				v.line, _ = strconv.Atoi(match[1])
+				v.column, _ = strconv.Atoi(match[2])
+				for _, doc := range file.Docs {
+					ast.Walk(v, doc)
+				}
+				de.Path = v.path
+			}
+		}
+
+		return de
+	}
+
+	return nil
+}
+
+// Visit visits the node, recording its path when it is at the position.
+func (v *yamlPathVisitor) Visit(node ast.Node) ast.Visitor {
+	if v.path != "" {
+		return nil
+	}
+
+	if tk := node.GetToken(); tk != nil && tk.Position.Line == v.line && tk.Position.Column == v.column {
+		v.path = node.GetPath()
+		return nil
+	}
+
+	return v
+}
+
+// yamlDepth returns the nesting depth of the mappings and sequences of the node.
+func yamlDepth(node ast.Node) int {
+	depth := 0
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		for _, value := range n.Values {
+			if d := yamlDepth(value.Value); d > depth {
+				depth = d
+			}
+		}
+		depth++
+	case *ast.MappingValueNode:
+		depth = yamlDepth(n.Value) + 1
+	case *ast.SequenceNode:
+		for _, value := range n.Values {
+			if d := yamlDepth(value); d > depth {
+				depth = d
+			}
+		}
+		depth++
+	case *ast.TagNode:
+		depth = yamlDepth(n.Value)
+	case *ast.AnchorNode:
+		depth = yamlDepth(n.Value)
+	}
+
+	return depth
 }
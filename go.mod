@@ -3,13 +3,19 @@ module github.com/leliuga/data
 go 1.19
 
 require (
+	github.com/andybalholm/brotli v1.0.5
+	github.com/fxamacker/cbor/v2 v2.5.0
 	github.com/goccy/go-yaml v1.11.0
 	github.com/google/uuid v1.3.1
 	github.com/jinzhu/inflection v1.0.0
+	github.com/klauspost/compress v1.16.7
 	github.com/leliuga/validation v1.0.1
 	github.com/pkg/errors v0.9.1
 	github.com/vmihailenco/msgpack/v5 v5.3.5
 	golang.org/x/exp v0.0.0-20230817173708-d852ddb80c63
+	golang.org/x/sync v0.3.0
+	golang.org/x/text v0.13.0
+	google.golang.org/protobuf v1.31.0
 )
 
 require (
@@ -18,6 +24,7 @@ require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.19 // indirect
 	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
 	golang.org/x/sys v0.12.0 // indirect
 	golang.org/x/xerrors v0.0.0-20220907171357-04be3eba64a2 // indirect
 )
@@ -108,7 +108,7 @@ func (m Map[T]) Range(fn func(key string, value T) bool) {
 func (m Map[T]) String(sep, join string) string {
 	parts := make([]string, 0, m.Len())
 	for key, value := range m {
-		parts = append(parts, fmt.Sprintf("%s%s%s", key, sep, value))
+		parts = append(parts, fmt.Sprintf("%s%s%v", key, sep, value))
 	}
 
 	sort.Strings(parts)
@@ -26,6 +26,7 @@ const (
 	MimeTextJavaScriptCharsetUTF8
 	MimeApplicationXMLCharsetUTF8
 	MimeApplicationJSONCharsetUTF8
+	MimeTextEventStream
 )
 
 var (
@@ -48,6 +49,7 @@ var (
 		MimeTextJavaScriptCharsetUTF8:  "text/javascript; charset=utf-8",
 		MimeApplicationXMLCharsetUTF8:  "application/xml; charset=utf-8",
 		MimeApplicationJSONCharsetUTF8: "application/json; charset=utf-8",
+		MimeTextEventStream:            "text/event-stream",
 	}
 
 	// ErrMimeInvalid is returned if the HTTP mime is invalid.
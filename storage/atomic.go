@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// counterDeadlineSeparator separates the value of an emulated counter from its expiration time.
+	counterDeadlineSeparator = "@"
+)
+
+var (
+	// ErrNotInteger is returned when incrementing a key whose value is not an integer.
+	ErrNotInteger = errors.New("value is not an integer")
+)
+
+// Atomic returns the given storage as an IAtomicStorage. The storage is returned as is when it supports atomic
+// operations, otherwise they are emulated with striped locks. The emulation is only atomic among the callers sharing
+// the returned value. As the remaining expiration of a key can not be read, the emulated Increment stores the
+// expiration time of the counter after its value, e.g. "5@1700000000000000000" in unix nanoseconds, which
+// ParseInteger ignores.
+func Atomic(storage IStorage) IAtomicStorage {
+	if s, ok := storage.(IAtomicStorage); ok {
+		return s
+	}
+
+	return &atomicStorage{IStorage: storage}
+}
+
+// ParseInteger parses a counter value stored by Increment, ignoring the expiration time stored by its emulation.
+func ParseInteger(value []byte) (int64, error) {
+	v, _, err := parseCounter(value)
+
+	return v, err
+}
+
+// FormatInteger formats a counter value stored by Increment.
+func FormatInteger(value int64) []byte {
+	return strconv.AppendInt(nil, value, 10)
+}
+
+// SetNX stores the given value for the given key only when the key does not exist.
+func (a *atomicStorage) SetNX(key string, val []byte, exp time.Duration) (bool, error) {
+	if len(key) == 0 || len(val) == 0 {
+		return false, nil
+	}
+
+	mutex := a.stripe(key)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	current, err := a.Get(key)
+	if err != nil || current != nil {
+		return false, err
+	}
+
+	return true, a.Set(key, val, exp)
+}
+
+// CompareAndSwap stores the new value for the given key only when the current value equals the old one.
+func (a *atomicStorage) CompareAndSwap(key string, old, new []byte, exp time.Duration) (bool, error) {
+	if len(key) == 0 {
+		return false, nil
+	}
+
+	mutex := a.stripe(key)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	current, err := a.Get(key)
+	if err != nil {
+		return false, err
+	}
+
+	if (old == nil) != (current == nil) || !bytes.Equal(current, old) {
+		return false, nil
+	}
+
+	if len(new) == 0 {
+		return true, a.Delete(key)
+	}
+
+	return true, a.Set(key, new, exp)
+}
+
+// Increment adds delta to the integer stored for the given key and returns the new value.
+func (a *atomicStorage) Increment(key string, delta int64, exp time.Duration) (int64, error) {
+	if len(key) == 0 {
+		return 0, nil
+	}
+
+	mutex := a.stripe(key)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	current, err := a.Get(key)
+	if err != nil {
+		return 0, err
+	}
+
+	value, deadline, err := parseCounter(current)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	if current == nil || (deadline != 0 && deadline <= now.UnixNano()) {
+		value, deadline = 0, 0
+		if exp > 0 {
+			deadline = now.Add(exp).UnixNano()
+		}
+	}
+
+	value += delta
+
+	if deadline == 0 {
+		return value, a.Set(key, FormatInteger(value), 0)
+	}
+
+	b := strconv.AppendInt(append(FormatInteger(value), counterDeadlineSeparator...), deadline, 10)
+
+	return value, a.Set(key, b, time.Duration(deadline-now.UnixNano()))
+}
+
+// parseCounter parses a counter value and the expiration time in unix nanoseconds stored after it by the
+// emulated Increment, 0 meaning no expiration.
+func parseCounter(b []byte) (int64, int64, error) {
+	if b == nil {
+		return 0, 0, nil
+	}
+
+	value, deadline, found := strings.Cut(string(b), counterDeadlineSeparator)
+
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, 0, ErrNotInteger
+	}
+
+	var d int64
+	if found {
+		if d, err = strconv.ParseInt(deadline, 10, 64); err != nil {
+			return 0, 0, ErrNotInteger
+		}
+	}
+
+	return v, d, nil
+}
+
+// stripe returns the lock guarding the given key.
+func (a *atomicStorage) stripe(key string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return &a.stripes[h.Sum32()%uint32(len(a.stripes))]
+}
@@ -0,0 +1,60 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/leliuga/data/storage"
+	"github.com/leliuga/data/storage/memory"
+)
+
+// plainStorage hides the atomic operations of the wrapped storage, so they are emulated.
+type plainStorage struct {
+	storage.IStorage
+}
+
+func TestAtomicIncrementKeepsExpiration(t *testing.T) {
+	s := storage.Atomic(plainStorage{memory.New()})
+
+	if v, err := s.Increment("hits", 1, 100*time.Millisecond); err != nil || v != 1 {
+		t.Fatalf("Increment() = %d, %v, want 1", v, err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if v, err := s.Increment("hits", 1, 100*time.Millisecond); err != nil || v != 2 {
+		t.Fatalf("Increment() = %d, %v, want 2", v, err)
+	}
+
+	b, err := s.Get("hits")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if v, err := storage.ParseInteger(b); err != nil || v != 2 {
+		t.Fatalf("ParseInteger(%q) = %d, %v, want 2", b, v, err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if b, _ = s.Get("hits"); b != nil {
+		t.Fatalf("Get() = %q, want nil after the first expiration", b)
+	}
+	if v, err := s.Increment("hits", 1, 100*time.Millisecond); err != nil || v != 1 {
+		t.Fatalf("Increment() = %d, %v, want 1", v, err)
+	}
+}
+
+func TestAtomicIncrementWithoutExpiration(t *testing.T) {
+	s := storage.Atomic(plainStorage{memory.New()})
+
+	for want := int64(1); want <= 3; want++ {
+		if v, err := s.Increment("hits", 1, 0); err != nil || v != want {
+			t.Fatalf("Increment() = %d, %v, want %d", v, err, want)
+		}
+	}
+
+	if err := s.Set("text", []byte("a"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := s.Increment("text", 1, 0); err != storage.ErrNotInteger {
+		t.Fatalf("Increment() error = %v, want %v", err, storage.ErrNotInteger)
+	}
+}
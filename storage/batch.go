@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"time"
+)
+
+// Batch returns the given storage as an IBatchStorage. The storage is returned as is when it supports batch
+// operations, otherwise they are emulated one key at a time.
+func Batch(storage IStorage) IBatchStorage {
+	if s, ok := storage.(IBatchStorage); ok {
+		return s
+	}
+
+	return &batchStorage{storage}
+}
+
+// Scanner returns the given storage as an IScanStorage and whether it supports iteration.
+// Iteration can not be emulated on top of a plain IStorage.
+func Scanner(storage IStorage) (IScanStorage, bool) {
+	s, ok := storage.(IScanStorage)
+	return s, ok
+}
+
+// GetMany gets the values for the given keys.
+// Keys that do not exist are omitted from the result.
+func (b *batchStorage) GetMany(keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		value, err := b.Get(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if value != nil {
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+// SetMany stores the given values along with an expiration value, 0 means no expiration.
+// Empty keys or values will be ignored without an error.
+func (b *batchStorage) SetMany(entries map[string][]byte, exp time.Duration) error {
+	for key, value := range entries {
+		if err := b.Set(key, value, exp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteMany deletes the values for the given keys.
+// It returns no error if the storage does not contain some of the keys.
+func (b *batchStorage) DeleteMany(keys []string) error {
+	for _, key := range keys {
+		if err := b.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
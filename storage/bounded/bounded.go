@@ -0,0 +1,256 @@
+package bounded
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTooLarge is returned when a single key and value exceed the MaxBytes budget.
+	ErrTooLarge = errors.New("value exceeds the storage size budget")
+)
+
+// New creates a new bounded storage and starts its garbage collector.
+func New(config ...Config) (*Storage, error) {
+	cfg := configDefault(config...)
+	if _, ok := PolicyNames[cfg.Policy]; !ok {
+		return nil, ErrPolicyInvalid
+	}
+
+	s := &Storage{
+		config: cfg,
+		items:  make(map[string]*item),
+		policy: newPolicy(cfg.Policy),
+		done:   make(chan struct{}),
+	}
+
+	go s.gc()
+
+	return s, nil
+}
+
+// Get gets the value for the given key and records the access for the eviction policy.
+// `nil, nil` is returned when the key does not exist or is expired.
+func (s *Storage) Get(key string) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+
+	var removed []evicted
+
+	s.mutex.Lock()
+	it, ok := s.items[key]
+	if ok && it.expired(time.Now().UnixNano()) {
+		removed = append(removed, s.remove(it, ReasonExpired))
+		ok = false
+	}
+
+	var value []byte
+	if ok {
+		s.stats.Hits++
+		s.policy.touch(it)
+		value = clone(it.value)
+	} else {
+		s.stats.Misses++
+	}
+	s.mutex.Unlock()
+
+	s.notify(removed)
+
+	return value, nil
+}
+
+// Set stores the given value for the given key along with an expiration value, 0 means no expiration, evicting keys
+// when the storage is full. Empty key or value will be ignored without an error.
+func (s *Storage) Set(key string, val []byte, exp time.Duration) error {
+	if len(key) == 0 || len(val) == 0 {
+		return nil
+	}
+
+	size := int64(len(key) + len(val))
+	if s.config.MaxBytes > 0 && size > s.config.MaxBytes {
+		return ErrTooLarge
+	}
+
+	var expireAt int64
+	if exp > 0 {
+		expireAt = time.Now().Add(exp).UnixNano()
+	}
+
+	s.mutex.Lock()
+	if it, ok := s.items[key]; ok {
+		s.bytes += int64(len(val) - len(it.value))
+		it.value = clone(val)
+		it.expireAt = expireAt
+		s.policy.touch(it)
+	} else {
+		it = &item{key: key, value: clone(val), expireAt: expireAt}
+		s.items[key] = it
+		s.bytes += size
+		s.policy.add(it)
+	}
+
+	removed := s.evict(key)
+	s.mutex.Unlock()
+
+	s.notify(removed)
+
+	return nil
+}
+
+// Delete deletes the value for the given key.
+// It returns no error if the storage does not contain the key.
+func (s *Storage) Delete(key string) error {
+	if len(key) == 0 {
+		return nil
+	}
+
+	s.mutex.Lock()
+	if it, ok := s.items[key]; ok {
+		s.remove(it, ReasonInvalid)
+	}
+	s.mutex.Unlock()
+
+	return nil
+}
+
+// Reset resets the storage and delete all keys.
+func (s *Storage) Reset() error {
+	s.mutex.Lock()
+	s.items = make(map[string]*item)
+	s.policy.reset()
+	s.bytes = 0
+	s.mutex.Unlock()
+
+	return nil
+}
+
+// Close closes the storage and stops the garbage collector.
+func (s *Storage) Close() error {
+	s.once.Do(func() {
+		close(s.done)
+	})
+
+	return nil
+}
+
+// Stats returns a snapshot of the counters.
+func (s *Storage) Stats() Stats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := s.stats
+	stats.Entries = len(s.items)
+	stats.Bytes = s.bytes
+
+	return stats
+}
+
+// evict removes keys chosen by the policy until the storage fits its limits, expired keys first.
+// The given key, just written, is never evicted. The caller must hold the lock.
+func (s *Storage) evict(key string) (removed []evicted) {
+	if !s.full() {
+		return nil
+	}
+
+	now := time.Now().UnixNano()
+	for _, it := range s.items {
+		if it.expired(now) {
+			removed = append(removed, s.remove(it, ReasonExpired))
+		}
+	}
+
+	// the given key is set aside while evicting, so the next item is evicted in its place
+	var kept *item
+	for s.full() {
+		it := s.policy.victim()
+		if it == nil {
+			break
+		}
+
+		if it.key == key {
+			s.policy.remove(it)
+			kept = it
+			continue
+		}
+
+		removed = append(removed, s.remove(it, ReasonCapacity))
+	}
+
+	if kept != nil {
+		s.policy.restore(kept)
+	}
+
+	return removed
+}
+
+// full returns whether the storage exceeds its limits. The caller must hold the lock.
+func (s *Storage) full() bool {
+	return (s.config.MaxEntries > 0 && len(s.items) > s.config.MaxEntries) ||
+		(s.config.MaxBytes > 0 && s.bytes > s.config.MaxBytes)
+}
+
+// remove removes the item and counts the reason. The caller must hold the lock.
+func (s *Storage) remove(it *item, reason Reason) evicted {
+	delete(s.items, it.key)
+	s.policy.remove(it)
+	s.bytes -= int64(len(it.key) + len(it.value))
+
+	switch reason {
+	case ReasonCapacity:
+		s.stats.Evictions++
+	case ReasonExpired:
+		s.stats.Expirations++
+	}
+
+	return evicted{key: it.key, value: it.value, reason: reason}
+}
+
+// notify calls the OnEvict callback for the removed items. The caller must not hold the lock.
+func (s *Storage) notify(removed []evicted) {
+	if s.config.OnEvict == nil {
+		return
+	}
+
+	for _, e := range removed {
+		s.config.OnEvict(e.key, e.value, e.reason)
+	}
+}
+
+// gc periodically deletes the expired keys until the storage is closed.
+func (s *Storage) gc() {
+	ticker := time.NewTicker(s.config.GCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case t := <-ticker.C:
+			var removed []evicted
+
+			s.mutex.Lock()
+			for _, it := range s.items {
+				if it.expired(t.UnixNano()) {
+					removed = append(removed, s.remove(it, ReasonExpired))
+				}
+			}
+			s.mutex.Unlock()
+
+			s.notify(removed)
+		}
+	}
+}
+
+// expired returns whether the item is expired at the given time in unix nanoseconds.
+func (it *item) expired(now int64) bool {
+	return it.expireAt != 0 && it.expireAt <= now
+}
+
+// clone returns a copy of the given bytes.
+func clone(b []byte) []byte {
+	c := make([]byte, len(b))
+	copy(c, b)
+
+	return c
+}
@@ -0,0 +1,65 @@
+package bounded_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/leliuga/data/storage"
+	"github.com/leliuga/data/storage/bounded"
+	"github.com/leliuga/data/storage/storagetest"
+)
+
+func TestSuite(t *testing.T) {
+	for _, policy := range []bounded.Policy{bounded.PolicyLRU, bounded.PolicyLFU} {
+		policy := policy
+		t.Run(policy.String(), func(t *testing.T) {
+			storagetest.RunSuite(t, func(t *testing.T) storage.IStorage {
+				s, err := bounded.New(bounded.Config{Policy: policy, MaxEntries: 1 << 16})
+				if err != nil {
+					t.Fatalf("New() error = %v", err)
+				}
+
+				return s
+			})
+		})
+	}
+}
+
+func TestEvictKeepsLimit(t *testing.T) {
+	for _, policy := range []bounded.Policy{bounded.PolicyLRU, bounded.PolicyLFU} {
+		policy := policy
+		t.Run(policy.String(), func(t *testing.T) {
+			s, err := bounded.New(bounded.Config{Policy: policy, MaxEntries: 2})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			defer s.Close()
+
+			for _, key := range []string{"a", "b"} {
+				if err = s.Set(key, []byte(key), 0); err != nil {
+					t.Fatalf("Set() error = %v", err)
+				}
+				for i := 0; i < 2; i++ {
+					if _, err = s.Get(key); err != nil {
+						t.Fatalf("Get() error = %v", err)
+					}
+				}
+			}
+
+			for i := 0; i < 8; i++ {
+				key := fmt.Sprintf("key%d", i)
+				if err = s.Set(key, []byte(key), 0); err != nil {
+					t.Fatalf("Set() error = %v", err)
+				}
+
+				if got := s.Stats().Entries; got > 2 {
+					t.Fatalf("Stats().Entries = %d after %d sets, want at most 2", got, i+1)
+				}
+
+				if got, _ := s.Get(key); string(got) != key {
+					t.Fatalf("Get(%q) = %q, want the key just written", key, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package bounded
+
+import (
+	"bytes"
+	"errors"
+	"time"
+)
+
+const (
+	PolicyInvalid Policy = iota //
+	PolicyLRU                   // Evicts the least recently used key
+	PolicyLFU                   // Evicts the least frequently used key, ties broken by the least recently used
+)
+
+const (
+	ReasonInvalid  Reason = iota //
+	ReasonCapacity               // The key has been evicted to make room
+	ReasonExpired                // The key has expired
+)
+
+var (
+	// PolicyNames is a map of policy values to policy names.
+	PolicyNames = map[Policy]string{
+		PolicyLRU: "lru",
+		PolicyLFU: "lfu",
+	}
+
+	// ReasonNames is a map of reason values to reason names.
+	ReasonNames = map[Reason]string{
+		ReasonCapacity: "capacity",
+		ReasonExpired:  "expired",
+	}
+
+	// DefaultConfig is the default bounded storage configuration.
+	DefaultConfig = Config{
+		Policy:     PolicyLRU,
+		GCInterval: 10 * time.Second,
+	}
+
+	// ErrPolicyInvalid is returned when the eviction policy is invalid.
+	ErrPolicyInvalid = errors.New("invalid eviction policy")
+)
+
+// String policy to string
+func (p Policy) String() string {
+	return PolicyNames[p]
+}
+
+// MarshalJSON policy to json
+func (p Policy) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + p.String() + `"`), nil
+}
+
+// UnmarshalJSON policy from json
+func (p *Policy) UnmarshalJSON(b []byte) error {
+	*p = ParsePolicy(string(bytes.Trim(b, `"`)))
+
+	return nil
+}
+
+// ParsePolicy parses policy string.
+func ParsePolicy(name string) Policy {
+	for k, v := range PolicyNames {
+		if v == name {
+			return k
+		}
+	}
+
+	return PolicyInvalid
+}
+
+// String reason to string
+func (r Reason) String() string {
+	return ReasonNames[r]
+}
+
+// configDefault returns the first provided config with the unset values filled by DefaultConfig.
+func configDefault(config ...Config) Config {
+	if len(config) < 1 {
+		return DefaultConfig
+	}
+
+	cfg := config[0]
+	if cfg.Policy == PolicyInvalid {
+		cfg.Policy = DefaultConfig.Policy
+	}
+
+	if cfg.GCInterval <= 0 {
+		cfg.GCInterval = DefaultConfig.GCInterval
+	}
+
+	return cfg
+}
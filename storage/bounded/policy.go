@@ -0,0 +1,129 @@
+package bounded
+
+import (
+	"container/heap"
+	"container/list"
+)
+
+// newPolicy creates the given eviction policy.
+func newPolicy(p Policy) policy {
+	if p == PolicyLFU {
+		return &lfu{}
+	}
+
+	return &lru{list: list.New()}
+}
+
+// add starts tracking the item as the most recently used.
+func (l *lru) add(it *item) {
+	it.element = l.list.PushFront(it)
+}
+
+// touch marks the item as the most recently used.
+func (l *lru) touch(it *item) {
+	l.list.MoveToFront(it.element)
+}
+
+// remove stops tracking the item.
+func (l *lru) remove(it *item) {
+	l.list.Remove(it.element)
+	it.element = nil
+}
+
+// restore tracks again the item as the most recently used.
+func (l *lru) restore(it *item) {
+	l.add(it)
+}
+
+// victim returns the least recently used item.
+func (l *lru) victim() *item {
+	if e := l.list.Back(); e != nil {
+		return e.Value.(*item)
+	}
+
+	return nil
+}
+
+// reset stops tracking all items.
+func (l *lru) reset() {
+	l.list.Init()
+}
+
+// add starts tracking the item with a single access.
+func (l *lfu) add(it *item) {
+	l.tick++
+	it.hits = 1
+	it.tick = l.tick
+	heap.Push(l, it)
+}
+
+// touch counts an access to the item.
+func (l *lfu) touch(it *item) {
+	l.tick++
+	it.hits++
+	it.tick = l.tick
+	heap.Fix(l, it.index)
+}
+
+// remove stops tracking the item.
+func (l *lfu) remove(it *item) {
+	heap.Remove(l, it.index)
+}
+
+// restore tracks again the item with its accesses.
+func (l *lfu) restore(it *item) {
+	heap.Push(l, it)
+}
+
+// victim returns the least frequently used item.
+func (l *lfu) victim() *item {
+	if len(l.heap) == 0 {
+		return nil
+	}
+
+	return l.heap[0]
+}
+
+// reset stops tracking all items.
+func (l *lfu) reset() {
+	l.heap = nil
+}
+
+// Len implements heap.Interface.
+func (l *lfu) Len() int {
+	return len(l.heap)
+}
+
+// Less implements heap.Interface.
+func (l *lfu) Less(i, j int) bool {
+	if l.heap[i].hits != l.heap[j].hits {
+		return l.heap[i].hits < l.heap[j].hits
+	}
+
+	return l.heap[i].tick < l.heap[j].tick
+}
+
+// Swap implements heap.Interface.
+func (l *lfu) Swap(i, j int) {
+	l.heap[i], l.heap[j] = l.heap[j], l.heap[i]
+	l.heap[i].index = i
+	l.heap[j].index = j
+}
+
+// Push implements heap.Interface.
+func (l *lfu) Push(x any) {
+	it := x.(*item)
+	it.index = len(l.heap)
+	l.heap = append(l.heap, it)
+}
+
+// Pop implements heap.Interface.
+func (l *lfu) Pop() any {
+	n := len(l.heap) - 1
+	it := l.heap[n]
+	l.heap[n] = nil
+	l.heap = l.heap[:n]
+	it.index = -1
+
+	return it
+}
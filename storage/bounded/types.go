@@ -0,0 +1,112 @@
+package bounded
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type (
+	// Storage defines an in-memory storage bounded by a number of keys and a size in bytes, evicting keys by the
+	// configured policy once full. It implements storage.IStorage.
+	Storage struct {
+		config Config
+		items  map[string]*item
+		policy policy
+		bytes  int64
+		stats  Stats
+		done   chan struct{}
+		once   sync.Once
+		mutex  sync.Mutex
+	}
+
+	// Config defines the bounded storage configuration.
+	Config struct {
+		// Policy is the policy used to select the key to evict.
+		// Optional. Default is PolicyLRU.
+		Policy Policy `json:"policy" yaml:"Policy"`
+
+		// MaxEntries is the maximum number of keys, 0 means no limit.
+		// Optional. Default is 0.
+		MaxEntries int `json:"max_entries" yaml:"MaxEntries"`
+
+		// MaxBytes is the maximum total size of the keys and values in bytes, 0 means no limit.
+		// Optional. Default is 0.
+		MaxBytes int64 `json:"max_bytes" yaml:"MaxBytes"`
+
+		// GCInterval is the interval between garbage collections of expired keys.
+		// Optional. Default is 10 * time.Second.
+		GCInterval time.Duration `json:"gc_interval" yaml:"GCInterval"`
+
+		// OnEvict is called after a key has been evicted or has expired, outside the storage lock.
+		// Optional. Default is nil.
+		OnEvict func(key string, value []byte, reason Reason) `json:"-"`
+	}
+
+	// Stats defines the bounded storage counters.
+	Stats struct {
+		Hits        int64 `json:"hits"        yaml:"Hits"`
+		Misses      int64 `json:"misses"      yaml:"Misses"`
+		Evictions   int64 `json:"evictions"   yaml:"Evictions"`
+		Expirations int64 `json:"expirations" yaml:"Expirations"`
+		Entries     int   `json:"entries"     yaml:"Entries"`
+		Bytes       int64 `json:"bytes"       yaml:"Bytes"`
+	}
+
+	// Policy defines an eviction policy.
+	Policy uint8
+
+	// Reason defines why a key has been removed.
+	Reason uint8
+
+	// item defines a single stored value along with its expiration time in unix nanoseconds, 0 means no expiration,
+	// and the bookkeeping of the eviction policies.
+	item struct {
+		key      string
+		value    []byte
+		expireAt int64
+		element  *list.Element
+		index    int
+		hits     uint64
+		tick     uint64
+	}
+
+	// policy defines the ordering of the keys used to select the key to evict.
+	policy interface {
+		// add starts tracking the item.
+		add(*item)
+
+		// touch records an access to the item.
+		touch(*item)
+
+		// remove stops tracking the item.
+		remove(*item)
+
+		// restore tracks again an item stopped by remove, keeping its accesses.
+		restore(*item)
+
+		// victim returns the item to evict next, or nil when there is none.
+		victim() *item
+
+		// reset stops tracking all items.
+		reset()
+	}
+
+	// lru defines the least recently used policy.
+	lru struct {
+		list *list.List
+	}
+
+	// lfu defines the least frequently used policy, ties broken by the least recently used.
+	lfu struct {
+		heap []*item
+		tick uint64
+	}
+
+	// evicted defines a removed item waiting for the OnEvict callback.
+	evicted struct {
+		key    string
+		value  []byte
+		reason Reason
+	}
+)
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// WithContext returns the given storage as an IStorageContext. The storage is returned as is when it honours contexts,
+// otherwise each call fails fast on a done context and stops waiting when the context is done. A plain IStorage can
+// not be interrupted, so the abandoned call still runs to completion in the background.
+func WithContext(storage IStorage) IStorageContext {
+	if s, ok := storage.(IStorageContext); ok {
+		return s
+	}
+
+	return &contextStorage{storage: storage}
+}
+
+// WithoutContext returns the given storage as an IStorage. Each call gets a background context bounded by the given
+// timeout, 0 means no timeout.
+func WithoutContext(storage IStorageContext, timeout time.Duration) IStorage {
+	if s, ok := storage.(IStorage); ok && timeout == 0 {
+		return s
+	}
+
+	return &plainStorage{storage: storage, timeout: timeout}
+}
+
+// GetContext gets the value for the given key.
+func (c *contextStorage) GetContext(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+
+	err := await(ctx, func() error {
+		v, err := c.storage.Get(key)
+		value = v
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// SetContext stores the given value for the given key along with an expiration value, 0 means no expiration.
+func (c *contextStorage) SetContext(ctx context.Context, key string, val []byte, exp time.Duration) error {
+	return await(ctx, func() error {
+		return c.storage.Set(key, val, exp)
+	})
+}
+
+// DeleteContext deletes the value for the given key.
+func (c *contextStorage) DeleteContext(ctx context.Context, key string) error {
+	return await(ctx, func() error {
+		return c.storage.Delete(key)
+	})
+}
+
+// ResetContext resets the storage and delete all keys.
+func (c *contextStorage) ResetContext(ctx context.Context) error {
+	return await(ctx, c.storage.Reset)
+}
+
+// Close closes the storage.
+func (c *contextStorage) Close() error {
+	return c.storage.Close()
+}
+
+// Get gets the value for the given key.
+func (p *plainStorage) Get(key string) ([]byte, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.storage.GetContext(ctx, key)
+}
+
+// Set stores the given value for the given key along with an expiration value, 0 means no expiration.
+func (p *plainStorage) Set(key string, val []byte, exp time.Duration) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.storage.SetContext(ctx, key, val, exp)
+}
+
+// Delete deletes the value for the given key.
+func (p *plainStorage) Delete(key string) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.storage.DeleteContext(ctx, key)
+}
+
+// Reset resets the storage and delete all keys.
+func (p *plainStorage) Reset() error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.storage.ResetContext(ctx)
+}
+
+// Close closes the storage.
+func (p *plainStorage) Close() error {
+	return p.storage.Close()
+}
+
+// context returns a background context bounded by the timeout.
+func (p *plainStorage) context() (context.Context, context.CancelFunc) {
+	if p.timeout > 0 {
+		return context.WithTimeout(context.Background(), p.timeout)
+	}
+
+	return context.WithCancel(context.Background())
+}
+
+// await runs fn and waits for its result or for the context to be done, whichever happens first.
+func await(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// a context that can never be done needs no goroutine
+	if ctx.Done() == nil {
+		return fn()
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- fn()
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
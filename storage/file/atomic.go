@@ -0,0 +1,99 @@
+package file
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/leliuga/data/storage"
+)
+
+// SetNX stores the given value for the given key only when the key does not exist, and reports whether it was stored.
+// Empty key or value will be ignored without an error.
+func (s *Storage) SetNX(key string, val []byte, exp time.Duration) (bool, error) {
+	if len(key) == 0 || len(val) == 0 {
+		return false, nil
+	}
+
+	expireAt := expiration(exp)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return false, ErrClosed
+	}
+
+	current, _, err := s.current(key)
+	if err != nil || current != nil {
+		return false, err
+	}
+
+	return true, s.set(key, val, expireAt)
+}
+
+// CompareAndSwap stores the new value for the given key only when the current value equals the old one, and reports
+// whether it was stored. A nil old value matches a key that does not exist, an empty new value deletes the key.
+func (s *Storage) CompareAndSwap(key string, old, new []byte, exp time.Duration) (bool, error) {
+	if len(key) == 0 {
+		return false, nil
+	}
+
+	expireAt := expiration(exp)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return false, ErrClosed
+	}
+
+	current, _, err := s.current(key)
+	if err != nil {
+		return false, err
+	}
+
+	if (old == nil) != (current == nil) || !bytes.Equal(current, old) {
+		return false, nil
+	}
+
+	if len(new) == 0 {
+		return true, s.delete(key)
+	}
+
+	return true, s.set(key, new, expireAt)
+}
+
+// Increment adds delta to the integer stored for the given key and returns the new value. A key that does not exist
+// counts from 0 and is created with the given expiration, an existing key keeps its expiration.
+func (s *Storage) Increment(key string, delta int64, exp time.Duration) (int64, error) {
+	if len(key) == 0 {
+		return 0, nil
+	}
+
+	expireAt := expiration(exp)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return 0, ErrClosed
+	}
+
+	current, pos, err := s.current(key)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := storage.ParseInteger(current)
+	if err != nil {
+		return 0, err
+	}
+
+	value += delta
+
+	if current != nil {
+		expireAt = pos.expireAt
+	}
+
+	return value, s.set(key, storage.FormatInteger(value), expireAt)
+}
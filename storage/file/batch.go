@@ -0,0 +1,127 @@
+package file
+
+import (
+	"time"
+)
+
+// GetMany gets the values for the given keys.
+// Keys that do not exist or are expired are omitted from the result.
+func (s *Storage) GetMany(keys []string) (map[string][]byte, error) {
+	now := time.Now().UnixNano()
+	values := make(map[string][]byte, len(keys))
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	for _, key := range keys {
+		pos, ok := s.index[key]
+		if !ok || pos.expired(now) {
+			continue
+		}
+
+		value := make([]byte, pos.length)
+		if _, err := s.file.ReadAt(value, pos.offset); err != nil {
+			return nil, err
+		}
+
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+// SetMany stores the given values along with an expiration value, 0 means no expiration.
+// Empty keys or values will be ignored without an error. The records are written with a single write.
+func (s *Storage) SetMany(entries map[string][]byte, exp time.Duration) error {
+	expireAt := expiration(exp)
+
+	var records []byte
+	positions := make(map[string]position, len(entries))
+	for key, value := range entries {
+		if len(key) == 0 || len(value) == 0 {
+			continue
+		}
+
+		positions[key] = position{
+			offset:   int64(len(records)) + headerSize + int64(len(key)),
+			size:     headerSize + int64(len(key)) + int64(len(value)),
+			length:   uint32(len(value)),
+			expireAt: expireAt,
+		}
+
+		records = append(records, encodeRecord(operationSet, key, value, expireAt)...)
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	offset, err := s.append(records)
+	if err != nil {
+		return err
+	}
+
+	for key, pos := range positions {
+		if old, ok := s.index[key]; ok {
+			s.dead += old.size
+		}
+
+		pos.offset += offset
+		s.index[key] = pos
+	}
+
+	return nil
+}
+
+// DeleteMany deletes the values for the given keys.
+// It returns no error if the storage does not contain some of the keys. The records are written with a single write.
+func (s *Storage) DeleteMany(keys []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	var records []byte
+	var dead int64
+	deleted := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		old, ok := s.index[key]
+		if _, seen := deleted[key]; !ok || seen {
+			continue
+		}
+
+		record := encodeRecord(operationDelete, key, nil, 0)
+		records = append(records, record...)
+		dead += old.size + int64(len(record))
+		deleted[key] = struct{}{}
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	if _, err := s.append(records); err != nil {
+		return err
+	}
+
+	for key := range deleted {
+		delete(s.index, key)
+	}
+
+	s.dead += dead
+
+	return nil
+}
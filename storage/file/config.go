@@ -0,0 +1,103 @@
+package file
+
+import (
+	"bytes"
+	"errors"
+	"time"
+)
+
+const (
+	SyncInvalid  SyncPolicy = iota //
+	SyncAlways                     // Flushes the log file after every write, the safest and the slowest policy
+	SyncInterval                   // Flushes the log file periodically, writes since the last flush may be lost on a crash
+	SyncNever                      // Leaves flushing to the operating system
+)
+
+var (
+	// SyncPolicyNames is a map of sync policy values to sync policy names.
+	SyncPolicyNames = map[SyncPolicy]string{
+		SyncAlways:   "always",
+		SyncInterval: "interval",
+		SyncNever:    "never",
+	}
+
+	// DefaultConfig is the default file-backed storage configuration.
+	DefaultConfig = Config{
+		Path:                "./storage.db",
+		FileMode:            0600,
+		Sync:                SyncInterval,
+		SyncInterval:        1 * time.Second,
+		GCInterval:          10 * time.Second,
+		CompactionThreshold: 4 << 20,
+		CompactionRatio:     0.5,
+	}
+
+	// ErrSyncPolicyInvalid is returned when the sync policy is invalid.
+	ErrSyncPolicyInvalid = errors.New("invalid sync policy")
+)
+
+// String sync policy to string
+func (p SyncPolicy) String() string {
+	return SyncPolicyNames[p]
+}
+
+// MarshalJSON sync policy to json
+func (p SyncPolicy) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + p.String() + `"`), nil
+}
+
+// UnmarshalJSON sync policy from json
+func (p *SyncPolicy) UnmarshalJSON(b []byte) error {
+	*p = ParseSyncPolicy(string(bytes.Trim(b, `"`)))
+
+	return nil
+}
+
+// ParseSyncPolicy parses sync policy string.
+func ParseSyncPolicy(name string) SyncPolicy {
+	for k, v := range SyncPolicyNames {
+		if v == name {
+			return k
+		}
+	}
+
+	return SyncInvalid
+}
+
+// configDefault returns the first provided config with the unset values filled by DefaultConfig.
+func configDefault(config ...Config) Config {
+	if len(config) < 1 {
+		return DefaultConfig
+	}
+
+	cfg := config[0]
+	if cfg.Path == "" {
+		cfg.Path = DefaultConfig.Path
+	}
+
+	if cfg.FileMode == 0 {
+		cfg.FileMode = DefaultConfig.FileMode
+	}
+
+	if cfg.Sync == SyncInvalid {
+		cfg.Sync = DefaultConfig.Sync
+	}
+
+	if cfg.SyncInterval <= 0 {
+		cfg.SyncInterval = DefaultConfig.SyncInterval
+	}
+
+	if cfg.GCInterval <= 0 {
+		cfg.GCInterval = DefaultConfig.GCInterval
+	}
+
+	if cfg.CompactionThreshold <= 0 {
+		cfg.CompactionThreshold = DefaultConfig.CompactionThreshold
+	}
+
+	if cfg.CompactionRatio <= 0 {
+		cfg.CompactionRatio = DefaultConfig.CompactionRatio
+	}
+
+	return cfg
+}
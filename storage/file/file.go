@@ -0,0 +1,447 @@
+package file
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	// ErrClosed is returned when the storage is used after it has been closed.
+	ErrClosed = errors.New("storage is closed")
+)
+
+// New opens the log file, recovers the keys it contains and starts the background garbage collector.
+// A torn or corrupted tail left by a crash is truncated, so the storage restarts from the last complete record.
+func New(config ...Config) (*Storage, error) {
+	cfg := configDefault(config...)
+	if _, ok := SyncPolicyNames[cfg.Sync]; !ok {
+		return nil, ErrSyncPolicyInvalid
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(cfg.Path, os.O_RDWR|os.O_CREATE, cfg.FileMode)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Storage{
+		config: cfg,
+		file:   f,
+		index:  make(map[string]position),
+		done:   make(chan struct{}),
+	}
+
+	if err = s.recover(); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("recover %s: %w", cfg.Path, err)
+	}
+
+	go s.run()
+
+	return s, nil
+}
+
+// Get gets the value for the given key.
+// `nil, nil` is returned when the key does not exist or is expired.
+func (s *Storage) Get(key string) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	value, _, err := s.current(key)
+
+	return value, err
+}
+
+// Set stores the given value for the given key along with an expiration value, 0 means no expiration.
+// Empty key or value will be ignored without an error.
+func (s *Storage) Set(key string, val []byte, exp time.Duration) error {
+	if len(key) == 0 || len(val) == 0 {
+		return nil
+	}
+
+	expireAt := expiration(exp)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	return s.set(key, val, expireAt)
+}
+
+// Delete deletes the value for the given key.
+// It returns no error if the storage does not contain the key.
+func (s *Storage) Delete(key string) error {
+	if len(key) == 0 {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	return s.delete(key)
+}
+
+// Reset resets the storage and delete all keys.
+func (s *Storage) Reset() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	if err := s.file.Truncate(0); err != nil {
+		return err
+	}
+
+	s.index = make(map[string]position)
+	s.size = 0
+	s.dead = 0
+
+	return s.file.Sync()
+}
+
+// Close closes the storage, stops the background garbage collector and flushes the log file.
+func (s *Storage) Close() error {
+	var err error
+
+	s.once.Do(func() {
+		close(s.done)
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		s.closed = true
+		if err = s.file.Sync(); err != nil {
+			_ = s.file.Close()
+			return
+		}
+
+		err = s.file.Close()
+	})
+
+	return err
+}
+
+// Sync flushes the log file to the disk.
+func (s *Storage) Sync() error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	return s.file.Sync()
+}
+
+// Compact rewrites the log file with only the live keys, reclaiming the space of overwritten, deleted and expired
+// values. The new log is written beside the current one and atomically renamed over it.
+func (s *Storage) Compact() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	return s.compact()
+}
+
+// set appends a record storing the value for the key. The caller must hold the write lock.
+func (s *Storage) set(key string, val []byte, expireAt int64) error {
+	offset, err := s.append(encodeRecord(operationSet, key, val, expireAt))
+	if err != nil {
+		return err
+	}
+
+	if old, ok := s.index[key]; ok {
+		s.dead += old.size
+	}
+
+	s.index[key] = position{
+		offset:   offset + headerSize + int64(len(key)),
+		size:     headerSize + int64(len(key)) + int64(len(val)),
+		length:   uint32(len(val)),
+		expireAt: expireAt,
+	}
+
+	return nil
+}
+
+// delete appends a record deleting the key when it exists. The caller must hold the write lock.
+func (s *Storage) delete(key string) error {
+	old, ok := s.index[key]
+	if !ok {
+		return nil
+	}
+
+	record := encodeRecord(operationDelete, key, nil, 0)
+	if _, err := s.append(record); err != nil {
+		return err
+	}
+
+	delete(s.index, key)
+	s.dead += old.size + int64(len(record))
+
+	return nil
+}
+
+// current returns the live value and position for the key, or nil when it does not exist or is expired.
+// The caller must hold the lock.
+func (s *Storage) current(key string) ([]byte, position, error) {
+	pos, ok := s.index[key]
+	if !ok || pos.expired(time.Now().UnixNano()) {
+		return nil, pos, nil
+	}
+
+	value := make([]byte, pos.length)
+	if _, err := s.file.ReadAt(value, pos.offset); err != nil {
+		return nil, pos, err
+	}
+
+	return value, pos, nil
+}
+
+// append writes the record at the end of the log file and returns its offset.
+func (s *Storage) append(record []byte) (int64, error) {
+	offset := s.size
+	if _, err := s.file.WriteAt(record, offset); err != nil {
+		// drop a partially written record, recovery would truncate it anyway
+		_ = s.file.Truncate(offset)
+		return 0, err
+	}
+
+	s.size += int64(len(record))
+
+	if s.config.Sync == SyncAlways {
+		if err := s.file.Sync(); err != nil {
+			return 0, err
+		}
+	}
+
+	return offset, nil
+}
+
+// recover rebuilds the index by replaying the log file from the beginning.
+func (s *Storage) recover() error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixNano()
+	total := info.Size()
+	reader := bufio.NewReader(io.NewSectionReader(s.file, 0, total))
+
+	var offset int64
+	for {
+		h, key, _, err := readRecord(reader, total-offset)
+		if err == io.EOF {
+			break
+		}
+
+		if err == io.ErrUnexpectedEOF || errors.Is(err, ErrRecordCorrupted) {
+			if err = s.file.Truncate(offset); err != nil {
+				return err
+			}
+
+			if err = s.file.Sync(); err != nil {
+				return err
+			}
+
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if old, ok := s.index[key]; ok {
+			s.dead += old.size
+			delete(s.index, key)
+		}
+
+		pos := position{
+			offset:   offset + headerSize + int64(h.keySize),
+			size:     h.size(),
+			length:   h.valueSize,
+			expireAt: h.expireAt,
+		}
+
+		if h.operation == operationDelete || pos.expired(now) {
+			s.dead += pos.size
+		} else {
+			s.index[key] = pos
+		}
+
+		offset += h.size()
+	}
+
+	s.size = offset
+
+	return nil
+}
+
+// compact rewrites the log file with only the live keys. The caller must hold the write lock.
+func (s *Storage) compact() (err error) {
+	path := s.config.Path + ".compact"
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, s.config.FileMode)
+	if err != nil {
+		return err
+	}
+
+	renamed := false
+	defer func() {
+		if err != nil && !renamed {
+			_ = f.Close()
+			_ = os.Remove(path)
+		}
+	}()
+
+	now := time.Now().UnixNano()
+	index := make(map[string]position, len(s.index))
+	writer := bufio.NewWriter(f)
+
+	var size int64
+	for key, pos := range s.index {
+		if pos.expired(now) {
+			continue
+		}
+
+		value := make([]byte, pos.length)
+		if _, err = s.file.ReadAt(value, pos.offset); err != nil {
+			return err
+		}
+
+		record := encodeRecord(operationSet, key, value, pos.expireAt)
+		if _, err = writer.Write(record); err != nil {
+			return err
+		}
+
+		index[key] = position{
+			offset:   size + headerSize + int64(len(key)),
+			size:     int64(len(record)),
+			length:   pos.length,
+			expireAt: pos.expireAt,
+		}
+
+		size += int64(len(record))
+	}
+
+	if err = writer.Flush(); err != nil {
+		return err
+	}
+
+	if err = f.Sync(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(path, s.config.Path); err != nil {
+		return err
+	}
+
+	renamed = true
+
+	_ = s.file.Close()
+	s.file = f
+	s.index = index
+	s.size = size
+	s.dead = 0
+
+	return syncDir(filepath.Dir(s.config.Path))
+}
+
+// run runs the background garbage collector and the periodic flush until the storage is closed.
+func (s *Storage) run() {
+	gc := time.NewTicker(s.config.GCInterval)
+	defer gc.Stop()
+
+	var flush <-chan time.Time
+	if s.config.Sync == SyncInterval {
+		ticker := time.NewTicker(s.config.SyncInterval)
+		defer ticker.Stop()
+		flush = ticker.C
+	}
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-flush:
+			_ = s.Sync()
+		case t := <-gc.C:
+			s.gc(t.UnixNano())
+		}
+	}
+}
+
+// gc drops the keys expired at the given time in unix nanoseconds and compacts the log when enough space is stale.
+func (s *Storage) gc(now int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	for key, pos := range s.index {
+		if pos.expired(now) {
+			delete(s.index, key)
+			s.dead += pos.size
+		}
+	}
+
+	if s.dead >= s.config.CompactionThreshold && float64(s.dead) >= float64(s.size)*s.config.CompactionRatio {
+		_ = s.compact()
+	}
+}
+
+// expiration returns the expiration time in unix nanoseconds for the given duration, 0 means no expiration.
+func expiration(exp time.Duration) int64 {
+	if exp > 0 {
+		return time.Now().Add(exp).UnixNano()
+	}
+
+	return 0
+}
+
+// expired returns whether the value is expired at the given time in unix nanoseconds.
+func (p position) expired(now int64) bool {
+	return p.expireAt != 0 && p.expireAt <= now
+}
+
+// syncDir flushes the directory entry changes, such as a rename, to the disk.
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer dir.Close()
+
+	return dir.Sync()
+}
@@ -0,0 +1,222 @@
+package file_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leliuga/data/storage"
+	"github.com/leliuga/data/storage/file"
+	"github.com/leliuga/data/storage/storagetest"
+)
+
+func TestSuite(t *testing.T) {
+	storagetest.RunSuite(t, func(t *testing.T) storage.IStorage {
+		s, err := file.New(file.Config{Path: filepath.Join(t.TempDir(), "storage.db")})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+
+		return s
+	})
+}
+
+func open(t *testing.T, path string) *file.Storage {
+	t.Helper()
+
+	s, err := file.New(file.Config{Path: path, Sync: file.SyncAlways})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return s
+}
+
+func size(t *testing.T, path string) int64 {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	return info.Size()
+}
+
+func assertValues(t *testing.T, s storage.IStorage, want map[string]string) {
+	t.Helper()
+
+	for key, value := range want {
+		got, err := s.Get(key)
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v", key, err)
+		}
+
+		if (value == "" && got != nil) || (value != "" && string(got) != value) {
+			t.Errorf("Get(%q) = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestRecoverTornTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.db")
+
+	s := open(t, path)
+	for _, key := range []string{"a", "b"} {
+		if err := s.Set(key, []byte("value "+key), 0); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	valid := size(t, path)
+
+	// the last record is written then torn, keeping only its first half
+	s = open(t, path)
+	if err := s.Set("c", []byte("value c"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	torn := data[:valid+(int64(len(data))-valid)/2]
+	if err = os.WriteFile(path, torn, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	s = open(t, path)
+	defer s.Close()
+
+	assertValues(t, s, map[string]string{"a": "value a", "b": "value b", "c": ""})
+	if got := size(t, path); got != valid {
+		t.Errorf("size = %d, want the torn record truncated to %d", got, valid)
+	}
+
+	if err = s.Set("d", []byte("value d"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	assertValues(t, s, map[string]string{"a": "value a", "d": "value d"})
+}
+
+func TestRecoverCorruptedTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.db")
+
+	s := open(t, path)
+	if err := s.Set("a", []byte("value a"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	valid := size(t, path)
+
+	s = open(t, path)
+	if err := s.Set("b", []byte("value b"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	s = open(t, path)
+	defer s.Close()
+
+	assertValues(t, s, map[string]string{"a": "value a", "b": ""})
+	if got := size(t, path); got != valid {
+		t.Errorf("size = %d, want the corrupted record truncated to %d", got, valid)
+	}
+}
+
+func TestCompactReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.db")
+
+	s := open(t, path)
+	for i := 0; i < 10; i++ {
+		for _, key := range []string{"a", "b", "c"} {
+			if err := s.Set(key, []byte(fmt.Sprintf("%s %d", key, i)), 0); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+		}
+	}
+	if err := s.Delete("c"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	before := size(t, path)
+	if err := s.Compact(); err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+	if after := size(t, path); after >= before {
+		t.Errorf("size = %d after Compact(), want less than %d", after, before)
+	}
+
+	want := map[string]string{"a": "a 9", "b": "b 9", "c": ""}
+	assertValues(t, s, want)
+
+	if err := s.Set("d", []byte("d 0"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	want["d"] = "d 0"
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	s = open(t, path)
+	defer s.Close()
+
+	assertValues(t, s, want)
+	if _, err := os.Stat(path + ".compact"); !os.IsNotExist(err) {
+		t.Errorf("Stat() error = %v, want the compaction file renamed", err)
+	}
+}
+
+func TestDeleteReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.db")
+
+	s := open(t, path)
+	for _, key := range []string{"a", "b"} {
+		if err := s.Set(key, []byte("value "+key), 0); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	if err := s.Delete("a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if _, err := s.Get("b"); err != file.ErrClosed {
+		t.Errorf("Get() error = %v after Close(), want %v", err, file.ErrClosed)
+	}
+
+	s = open(t, path)
+	defer s.Close()
+
+	assertValues(t, s, map[string]string{"a": "", "b": "value b"})
+
+	keys, err := s.Keys("")
+	if err != nil {
+		t.Fatalf("Keys() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "b" {
+		t.Errorf("Keys() = %q, want [b]", keys)
+	}
+}
@@ -0,0 +1,90 @@
+package file
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+const (
+	operationInvalid operation = iota //
+	operationSet                      // The record stores a value for a key
+	operationDelete                   // The record deletes a key
+
+	// headerSize is the size of an encoded record header: checksum, operation, expiration, key and value sizes.
+	headerSize = 4 + 1 + 8 + 4 + 4
+)
+
+var (
+	// ErrRecordCorrupted is returned when a log record does not match its checksum.
+	ErrRecordCorrupted = errors.New("corrupted log record")
+)
+
+// encodeRecord encodes a single log record.
+func encodeRecord(op operation, key string, value []byte, expireAt int64) []byte {
+	b := make([]byte, headerSize+len(key)+len(value))
+	b[4] = byte(op)
+	binary.LittleEndian.PutUint64(b[5:13], uint64(expireAt))
+	binary.LittleEndian.PutUint32(b[13:17], uint32(len(key)))
+	binary.LittleEndian.PutUint32(b[17:21], uint32(len(value)))
+	copy(b[headerSize:], key)
+	copy(b[headerSize+len(key):], value)
+	binary.LittleEndian.PutUint32(b[0:4], crc32.ChecksumIEEE(b[4:]))
+
+	return b
+}
+
+// decodeHeader decodes a record header from the given bytes.
+func decodeHeader(b []byte) header {
+	return header{
+		checksum:  binary.LittleEndian.Uint32(b[0:4]),
+		operation: operation(b[4]),
+		expireAt:  int64(binary.LittleEndian.Uint64(b[5:13])),
+		keySize:   binary.LittleEndian.Uint32(b[13:17]),
+		valueSize: binary.LittleEndian.Uint32(b[17:21]),
+	}
+}
+
+// readRecord reads the next log record, limited to the given remaining bytes of the log. It returns io.EOF at a clean
+// end of the log, and io.ErrUnexpectedEOF or ErrRecordCorrupted when the record is torn or damaged, typically by a
+// crash in the middle of a write.
+func readRecord(r *bufio.Reader, remaining int64) (h header, key string, value []byte, err error) {
+	b := make([]byte, headerSize)
+	if _, err = io.ReadFull(r, b); err != nil {
+		return h, "", nil, err
+	}
+
+	h = decodeHeader(b)
+	if h.operation != operationSet && h.operation != operationDelete {
+		return h, "", nil, ErrRecordCorrupted
+	}
+
+	if h.size() > remaining {
+		return h, "", nil, io.ErrUnexpectedEOF
+	}
+
+	payload := make([]byte, int(h.keySize)+int(h.valueSize))
+	if _, err = io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return h, "", nil, err
+	}
+
+	checksum := crc32.NewIEEE()
+	_, _ = checksum.Write(b[4:])
+	_, _ = checksum.Write(payload)
+	if checksum.Sum32() != h.checksum {
+		return h, "", nil, ErrRecordCorrupted
+	}
+
+	return h, string(payload[:h.keySize]), payload[h.keySize:], nil
+}
+
+// size returns the size of the encoded record.
+func (h header) size() int64 {
+	return headerSize + int64(h.keySize) + int64(h.valueSize)
+}
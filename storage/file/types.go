@@ -0,0 +1,76 @@
+package file
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+type (
+	// Storage defines an embedded file-backed storage built on an append-only log. It implements storage.IStorage.
+	Storage struct {
+		config Config
+		file   *os.File
+		index  map[string]position
+		size   int64
+		dead   int64
+		closed bool
+		done   chan struct{}
+		once   sync.Once
+		mutex  sync.RWMutex
+	}
+
+	// Config defines the file-backed storage configuration.
+	Config struct {
+		// Path is the path of the log file, it is created when it does not exist.
+		// Optional. Default is "./storage.db".
+		Path string `json:"path" yaml:"Path"`
+
+		// FileMode is the permission of the log file when it is created.
+		// Optional. Default is 0600.
+		FileMode os.FileMode `json:"file_mode" yaml:"FileMode"`
+
+		// Sync is the policy used to flush written records to the disk.
+		// Optional. Default is SyncInterval.
+		Sync SyncPolicy `json:"sync" yaml:"Sync"`
+
+		// SyncInterval is the interval between flushes when Sync is SyncInterval.
+		// Optional. Default is 1 * time.Second.
+		SyncInterval time.Duration `json:"sync_interval" yaml:"SyncInterval"`
+
+		// GCInterval is the interval between garbage collections of expired keys.
+		// Optional. Default is 10 * time.Second.
+		GCInterval time.Duration `json:"gc_interval" yaml:"GCInterval"`
+
+		// CompactionThreshold is the minimum amount of stale bytes in the log before it is compacted.
+		// Optional. Default is 4 MiB.
+		CompactionThreshold int64 `json:"compaction_threshold" yaml:"CompactionThreshold"`
+
+		// CompactionRatio is the minimum ratio of stale bytes to the log size before it is compacted.
+		// Optional. Default is 0.5.
+		CompactionRatio float64 `json:"compaction_ratio" yaml:"CompactionRatio"`
+	}
+
+	// SyncPolicy defines when written records are flushed to the disk.
+	SyncPolicy uint8
+
+	// position defines the location of a live value in the log file.
+	position struct {
+		offset   int64
+		size     int64
+		length   uint32
+		expireAt int64
+	}
+
+	// header defines the fixed size prefix of a single log record.
+	header struct {
+		checksum  uint32
+		operation operation
+		expireAt  int64
+		keySize   uint32
+		valueSize uint32
+	}
+
+	// operation defines the kind of log record.
+	operation uint8
+)
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/leliuga/data/storage"
+)
+
+// SetNX stores the given value for the given key only when the key does not exist, and reports whether it was stored.
+// Empty key or value will be ignored without an error.
+func (s *Storage) SetNX(key string, val []byte, exp time.Duration) (bool, error) {
+	if len(key) == 0 || len(val) == 0 {
+		return false, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.current(key) != nil {
+		return false, nil
+	}
+
+	s.entries[key] = entry{value: clone(val), expireAt: expiration(exp)}
+
+	return true, nil
+}
+
+// CompareAndSwap stores the new value for the given key only when the current value equals the old one, and reports
+// whether it was stored. A nil old value matches a key that does not exist, an empty new value deletes the key.
+func (s *Storage) CompareAndSwap(key string, old, new []byte, exp time.Duration) (bool, error) {
+	if len(key) == 0 {
+		return false, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current := s.current(key)
+	if (old == nil) != (current == nil) || !bytes.Equal(current, old) {
+		return false, nil
+	}
+
+	if len(new) == 0 {
+		delete(s.entries, key)
+		return true, nil
+	}
+
+	s.entries[key] = entry{value: clone(new), expireAt: expiration(exp)}
+
+	return true, nil
+}
+
+// Increment adds delta to the integer stored for the given key and returns the new value. A key that does not exist
+// counts from 0 and is created with the given expiration, an existing key keeps its expiration.
+func (s *Storage) Increment(key string, delta int64, exp time.Duration) (int64, error) {
+	if len(key) == 0 {
+		return 0, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current := s.current(key)
+	value, err := storage.ParseInteger(current)
+	if err != nil {
+		return 0, err
+	}
+
+	value += delta
+
+	expireAt := expiration(exp)
+	if current != nil {
+		expireAt = s.entries[key].expireAt
+	}
+
+	s.entries[key] = entry{value: storage.FormatInteger(value), expireAt: expireAt}
+
+	return value, nil
+}
+
+// current returns the live value for the given key or nil. The caller must hold the lock.
+func (s *Storage) current(key string) []byte {
+	e, ok := s.entries[key]
+	if !ok || e.expired(time.Now().UnixNano()) {
+		return nil
+	}
+
+	return e.value
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"time"
+)
+
+// GetMany gets the values for the given keys.
+// Keys that do not exist or are expired are omitted from the result.
+func (s *Storage) GetMany(keys []string) (map[string][]byte, error) {
+	now := time.Now().UnixNano()
+	values := make(map[string][]byte, len(keys))
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, key := range keys {
+		if e, ok := s.entries[key]; ok && !e.expired(now) {
+			values[key] = clone(e.value)
+		}
+	}
+
+	return values, nil
+}
+
+// SetMany stores the given values along with an expiration value, 0 means no expiration.
+// Empty keys or values will be ignored without an error.
+func (s *Storage) SetMany(entries map[string][]byte, exp time.Duration) error {
+	expireAt := expiration(exp)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for key, value := range entries {
+		if len(key) == 0 || len(value) == 0 {
+			continue
+		}
+
+		s.entries[key] = entry{value: clone(value), expireAt: expireAt}
+	}
+
+	return nil
+}
+
+// DeleteMany deletes the values for the given keys.
+// It returns no error if the storage does not contain some of the keys.
+func (s *Storage) DeleteMany(keys []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package memory
+
+import (
+	"time"
+)
+
+var (
+	// DefaultConfig is the default in-memory storage configuration.
+	DefaultConfig = Config{
+		GCInterval: 10 * time.Second,
+	}
+)
+
+// configDefault returns the first provided config with the unset values filled by DefaultConfig.
+func configDefault(config ...Config) Config {
+	if len(config) < 1 {
+		return DefaultConfig
+	}
+
+	cfg := config[0]
+	if cfg.GCInterval <= 0 {
+		cfg.GCInterval = DefaultConfig.GCInterval
+	}
+
+	return cfg
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"time"
+)
+
+// New creates a new in-memory storage and starts its garbage collector.
+func New(config ...Config) *Storage {
+	s := &Storage{
+		config:  configDefault(config...),
+		entries: make(map[string]entry),
+		done:    make(chan struct{}),
+	}
+
+	go s.gc()
+
+	return s
+}
+
+// Get gets the value for the given key.
+// `nil, nil` is returned when the key does not exist or is expired.
+func (s *Storage) Get(key string) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+
+	s.mutex.RLock()
+	e, ok := s.entries[key]
+	s.mutex.RUnlock()
+
+	if !ok || e.expired(time.Now().UnixNano()) {
+		return nil, nil
+	}
+
+	return clone(e.value), nil
+}
+
+// Set stores the given value for the given key along with an expiration value, 0 means no expiration.
+// Empty key or value will be ignored without an error.
+func (s *Storage) Set(key string, val []byte, exp time.Duration) error {
+	if len(key) == 0 || len(val) == 0 {
+		return nil
+	}
+
+	expireAt := expiration(exp)
+
+	s.mutex.Lock()
+	s.entries[key] = entry{value: clone(val), expireAt: expireAt}
+	s.mutex.Unlock()
+
+	return nil
+}
+
+// Delete deletes the value for the given key.
+// It returns no error if the storage does not contain the key.
+func (s *Storage) Delete(key string) error {
+	if len(key) == 0 {
+		return nil
+	}
+
+	s.mutex.Lock()
+	delete(s.entries, key)
+	s.mutex.Unlock()
+
+	return nil
+}
+
+// Reset resets the storage and delete all keys.
+func (s *Storage) Reset() error {
+	s.mutex.Lock()
+	s.entries = make(map[string]entry)
+	s.mutex.Unlock()
+
+	return nil
+}
+
+// Close closes the storage and stops the garbage collector.
+func (s *Storage) Close() error {
+	s.once.Do(func() {
+		close(s.done)
+	})
+
+	return nil
+}
+
+// Len returns the number of keys in the storage, including expired keys not yet collected.
+func (s *Storage) Len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.entries)
+}
+
+// gc periodically deletes the expired keys until the storage is closed.
+func (s *Storage) gc() {
+	ticker := time.NewTicker(s.config.GCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case t := <-ticker.C:
+			s.deleteExpired(t.UnixNano())
+		}
+	}
+}
+
+// deleteExpired deletes all keys expired at the given time in unix nanoseconds.
+func (s *Storage) deleteExpired(now int64) {
+	var expired []string
+
+	s.mutex.RLock()
+	for key, e := range s.entries {
+		if e.expired(now) {
+			expired = append(expired, key)
+		}
+	}
+	s.mutex.RUnlock()
+
+	if len(expired) == 0 {
+		return
+	}
+
+	s.mutex.Lock()
+	for _, key := range expired {
+		// the key may have been overwritten since the read pass
+		if e, ok := s.entries[key]; ok && e.expired(now) {
+			delete(s.entries, key)
+		}
+	}
+	s.mutex.Unlock()
+}
+
+// expiration returns the expiration time in unix nanoseconds for the given duration, 0 means no expiration.
+func expiration(exp time.Duration) int64 {
+	if exp > 0 {
+		return time.Now().Add(exp).UnixNano()
+	}
+
+	return 0
+}
+
+// expired returns whether the entry is expired at the given time in unix nanoseconds.
+func (e entry) expired(now int64) bool {
+	return e.expireAt != 0 && e.expireAt <= now
+}
+
+// clone returns a copy of the given bytes.
+func clone(b []byte) []byte {
+	c := make([]byte, len(b))
+	copy(c, b)
+
+	return c
+}
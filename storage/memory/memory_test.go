@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/leliuga/data/storage"
+	"github.com/leliuga/data/storage/memory"
+	"github.com/leliuga/data/storage/storagetest"
+)
+
+func TestSuite(t *testing.T) {
+	storagetest.RunSuite(t, func(t *testing.T) storage.IStorage {
+		return memory.New()
+	})
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Keys returns the sorted keys starting with the given prefix, an empty prefix matches all keys.
+// Expired keys are omitted.
+func (s *Storage) Keys(prefix string) ([]string, error) {
+	now := time.Now().UnixNano()
+
+	s.mutex.RLock()
+	keys := make([]string, 0, len(s.entries))
+	for key, e := range s.entries {
+		if strings.HasPrefix(key, prefix) && !e.expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	s.mutex.RUnlock()
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// Scan calls fn for each key starting with the given prefix in sorted order, until fn returns false.
+// It iterates over a snapshot of the matching keys, so the storage may be used from fn.
+func (s *Storage) Scan(prefix string, fn func(key string, value []byte) bool) error {
+	keys, err := s.Keys(prefix)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		value, err := s.Get(key)
+		if err != nil {
+			return err
+		}
+
+		// the key has been deleted or has expired since the snapshot
+		if value == nil {
+			continue
+		}
+
+		if !fn(key, value) {
+			break
+		}
+	}
+
+	return nil
+}
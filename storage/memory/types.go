@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// Storage defines an in-memory storage. It implements storage.IStorage.
+	Storage struct {
+		config  Config
+		entries map[string]entry
+		done    chan struct{}
+		once    sync.Once
+		mutex   sync.RWMutex
+	}
+
+	// Config defines the in-memory storage configuration.
+	Config struct {
+		// GCInterval is the interval between garbage collections of expired keys.
+		// Optional. Default is 10 * time.Second.
+		GCInterval time.Duration `json:"gc_interval" yaml:"GCInterval"`
+	}
+
+	// entry defines a single stored value along with its expiration time in unix nanoseconds, 0 means no expiration.
+	entry struct {
+		value    []byte
+		expireAt int64
+	}
+)
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/leliuga/data/storage"
+)
+
+const (
+	// compressedRaw marks a value stored as is, because it is too small or does not shrink.
+	compressedRaw byte = iota
+
+	// compressedFlate marks a value stored compressed with DEFLATE.
+	compressedFlate
+)
+
+var (
+	// ErrCompressedInvalid is returned when a stored value has not been written by Compressed.
+	ErrCompressedInvalid = errors.New("invalid compressed value")
+)
+
+// NewCompressed creates a new storage that compresses values of at least minSize bytes with the given flate level,
+// e.g. flate.DefaultCompression. Each stored value is prefixed with one byte telling whether it is compressed.
+func NewCompressed(s storage.IStorage, level, minSize int) (*Compressed, error) {
+	if _, err := flate.NewWriter(io.Discard, level); err != nil {
+		return nil, err
+	}
+
+	return &Compressed{
+		storage: s,
+		level:   level,
+		minSize: minSize,
+	}, nil
+}
+
+// Get gets and decompresses the value for the given key.
+func (c *Compressed) Get(key string) ([]byte, error) {
+	b, err := c.storage.Get(key)
+	if err != nil || b == nil {
+		return nil, err
+	}
+
+	switch b[0] {
+	case compressedRaw:
+		return b[1:], nil
+	case compressedFlate:
+		r := flate.NewReader(bytes.NewReader(b[1:]))
+		defer r.Close()
+
+		return io.ReadAll(r)
+	}
+
+	return nil, ErrCompressedInvalid
+}
+
+// Set compresses and stores the given value for the given key along with an expiration value, 0 means no expiration.
+func (c *Compressed) Set(key string, val []byte, exp time.Duration) error {
+	if len(key) == 0 || len(val) == 0 {
+		return nil
+	}
+
+	if len(val) >= c.minSize {
+		buffer := bytes.NewBuffer([]byte{compressedFlate})
+		w, err := flate.NewWriter(buffer, c.level)
+		if err != nil {
+			return err
+		}
+
+		if _, err = w.Write(val); err != nil {
+			return err
+		}
+
+		if err = w.Close(); err != nil {
+			return err
+		}
+
+		if buffer.Len() <= len(val) {
+			return c.storage.Set(key, buffer.Bytes(), exp)
+		}
+	}
+
+	return c.storage.Set(key, append([]byte{compressedRaw}, val...), exp)
+}
+
+// Delete deletes the value for the given key.
+func (c *Compressed) Delete(key string) error {
+	return c.storage.Delete(key)
+}
+
+// Reset resets the storage and delete all keys.
+func (c *Compressed) Reset() error {
+	return c.storage.Reset()
+}
+
+// Close closes the storage.
+func (c *Compressed) Close() error {
+	return c.storage.Close()
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/leliuga/data/storage"
+)
+
+var (
+	// ErrDecrypt is returned when a stored value can not be decrypted, e.g. it was tampered with or moved to another key.
+	ErrDecrypt = errors.New("unable to decrypt value")
+)
+
+// NewEncrypted creates a new storage that encrypts every value with AES-GCM.
+// The key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+func NewEncrypted(s storage.IStorage, key []byte) (*Encrypted, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Encrypted{
+		storage: s,
+		aead:    aead,
+	}, nil
+}
+
+// Get gets and decrypts the value for the given key.
+func (e *Encrypted) Get(key string) ([]byte, error) {
+	b, err := e.storage.Get(key)
+	if err != nil || b == nil {
+		return nil, err
+	}
+
+	size := e.aead.NonceSize()
+	if len(b) < size {
+		return nil, ErrDecrypt
+	}
+
+	// the key is authenticated, so a value can not be swapped between keys
+	value, err := e.aead.Open(nil, b[:size], b[size:], []byte(key))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	return value, nil
+}
+
+// Set encrypts and stores the given value for the given key along with an expiration value, 0 means no expiration.
+// The stored value is the random nonce followed by the sealed value.
+func (e *Encrypted) Set(key string, val []byte, exp time.Duration) error {
+	if len(key) == 0 || len(val) == 0 {
+		return nil
+	}
+
+	nonce := make([]byte, e.aead.NonceSize(), e.aead.NonceSize()+len(val)+e.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+
+	return e.storage.Set(key, e.aead.Seal(nonce, nonce, val, []byte(key)), exp)
+}
+
+// Delete deletes the value for the given key.
+func (e *Encrypted) Delete(key string) error {
+	return e.storage.Delete(key)
+}
+
+// Reset resets the storage and delete all keys.
+func (e *Encrypted) Reset() error {
+	return e.storage.Reset()
+}
+
+// Close closes the storage.
+func (e *Encrypted) Close() error {
+	return e.storage.Close()
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/leliuga/data/storage"
+)
+
+const (
+	OperationGet Operation = iota
+	OperationSet
+	OperationDelete
+	OperationReset
+	OperationClose
+
+	operationCount
+)
+
+var (
+	// OperationNames is a map of operation values to operation names.
+	OperationNames = map[Operation]string{
+		OperationGet:    "get",
+		OperationSet:    "set",
+		OperationDelete: "delete",
+		OperationReset:  "reset",
+		OperationClose:  "close",
+	}
+)
+
+// String operation to string
+func (o Operation) String() string {
+	return OperationNames[o]
+}
+
+// MarshalText operation to text, so it can be used as a json map key
+func (o Operation) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText operation from text
+func (o *Operation) UnmarshalText(b []byte) error {
+	for k, v := range OperationNames {
+		if v == string(bytes.ToLower(b)) {
+			*o = k
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// NewInstrumented creates a new storage that counts operations, and calls the observers after every operation.
+func NewInstrumented(s storage.IStorage, observers ...Observer) *Instrumented {
+	return &Instrumented{
+		storage:   s,
+		observers: observers,
+	}
+}
+
+// Get gets the value for the given key, counting a hit or a miss.
+func (i *Instrumented) Get(key string) ([]byte, error) {
+	start := time.Now()
+	value, err := i.storage.Get(key)
+
+	switch {
+	case err != nil:
+	case value == nil:
+		i.misses.Add(1)
+	default:
+		i.hits.Add(1)
+	}
+
+	i.observe(OperationGet, key, start, err)
+
+	return value, err
+}
+
+// Set stores the given value for the given key along with an expiration value, 0 means no expiration.
+func (i *Instrumented) Set(key string, val []byte, exp time.Duration) error {
+	start := time.Now()
+	err := i.storage.Set(key, val, exp)
+	if err == nil {
+		i.sets.Add(1)
+	}
+
+	i.observe(OperationSet, key, start, err)
+
+	return err
+}
+
+// Delete deletes the value for the given key.
+func (i *Instrumented) Delete(key string) error {
+	start := time.Now()
+	err := i.storage.Delete(key)
+	if err == nil {
+		i.deletes.Add(1)
+	}
+
+	i.observe(OperationDelete, key, start, err)
+
+	return err
+}
+
+// Reset resets the storage and delete all keys.
+func (i *Instrumented) Reset() error {
+	start := time.Now()
+	err := i.storage.Reset()
+	if err == nil {
+		i.resets.Add(1)
+	}
+
+	i.observe(OperationReset, "", start, err)
+
+	return err
+}
+
+// Close closes the storage.
+func (i *Instrumented) Close() error {
+	start := time.Now()
+	err := i.storage.Close()
+
+	i.observe(OperationClose, "", start, err)
+
+	return err
+}
+
+// Stats returns a snapshot of the counters. Latency is the total time spent in each operation.
+func (i *Instrumented) Stats() Stats {
+	latency := make(map[Operation]time.Duration, operationCount)
+	for op := Operation(0); op < operationCount; op++ {
+		latency[op] = time.Duration(i.latency[op].Load())
+	}
+
+	return Stats{
+		Hits:    i.hits.Load(),
+		Misses:  i.misses.Load(),
+		Sets:    i.sets.Load(),
+		Deletes: i.deletes.Load(),
+		Resets:  i.resets.Load(),
+		Errors:  i.errors.Load(),
+		Latency: latency,
+	}
+}
+
+// observe records the latency and the error of an operation and notifies the observers.
+func (i *Instrumented) observe(op Operation, key string, start time.Time, err error) {
+	duration := time.Since(start)
+	i.latency[op].Add(int64(duration))
+
+	if err != nil {
+		i.errors.Add(1)
+	}
+
+	for _, observer := range i.observers {
+		observer(op, key, duration, err)
+	}
+}
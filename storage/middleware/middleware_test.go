@@ -0,0 +1,88 @@
+package middleware_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/leliuga/data/storage"
+	"github.com/leliuga/data/storage/memory"
+	"github.com/leliuga/data/storage/middleware"
+	"github.com/leliuga/data/storage/storagetest"
+)
+
+func TestNamespacedSuite(t *testing.T) {
+	storagetest.RunSuite(t, func(t *testing.T) storage.IStorage {
+		s, err := middleware.NewNamespaced(memory.New(), "tenant")
+		if err != nil {
+			t.Fatalf("NewNamespaced() error = %v", err)
+		}
+
+		return s
+	})
+}
+
+func TestNamespacedResetIsolation(t *testing.T) {
+	backend := memory.New()
+	t1, err := middleware.NewNamespaced(backend, "t1")
+	if err != nil {
+		t.Fatalf("NewNamespaced() error = %v", err)
+	}
+	t10, err := middleware.NewNamespaced(backend, "t10")
+	if err != nil {
+		t.Fatalf("NewNamespaced() error = %v", err)
+	}
+
+	if err = t1.Set("a", []byte("1"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err = t10.Set("a", []byte("10"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if err = t1.Reset(); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+
+	if got, _ := t1.Get("a"); got != nil {
+		t.Errorf("t1 Get() = %q, want nil", got)
+	}
+	if got, _ := t10.Get("a"); string(got) != "10" {
+		t.Errorf("t10 Get() = %q, want %q", got, "10")
+	}
+}
+
+func TestNamespacedInvalid(t *testing.T) {
+	for _, namespace := range []string{"", "a:b"} {
+		if _, err := middleware.NewNamespaced(memory.New(), namespace); err != middleware.ErrNamespaceInvalid {
+			t.Errorf("NewNamespaced(%q) error = %v, want %v", namespace, err, middleware.ErrNamespaceInvalid)
+		}
+	}
+}
+
+func TestEncryptedSuite(t *testing.T) {
+	storagetest.RunSuite(t, func(t *testing.T) storage.IStorage {
+		s, err := middleware.NewEncrypted(memory.New(), bytes.Repeat([]byte{1}, 32))
+		if err != nil {
+			t.Fatalf("NewEncrypted() error = %v", err)
+		}
+
+		return s
+	})
+}
+
+func TestCompressedSuite(t *testing.T) {
+	storagetest.RunSuite(t, func(t *testing.T) storage.IStorage {
+		s, err := middleware.NewCompressed(memory.New(), 0, 0)
+		if err != nil {
+			t.Fatalf("NewCompressed() error = %v", err)
+		}
+
+		return s
+	})
+}
+
+func TestInstrumentedSuite(t *testing.T) {
+	storagetest.RunSuite(t, func(t *testing.T) storage.IStorage {
+		return middleware.NewInstrumented(memory.New())
+	})
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/leliuga/data/storage"
+)
+
+const (
+	// NamespaceSeparator separates the namespace from the key, so no namespace is the prefix of another one.
+	NamespaceSeparator = ":"
+)
+
+var (
+	// ErrNamespaceInvalid is returned when a namespace is empty or contains the NamespaceSeparator.
+	ErrNamespaceInvalid = errors.New("invalid namespace")
+
+	// ErrResetUnsupported is returned when a namespace can not be reset because its backend can not list keys.
+	ErrResetUnsupported = errors.New("reset requires a storage able to scan keys")
+)
+
+// NewNamespaced creates a new storage that prefixes every key with the namespace followed by the NamespaceSeparator,
+// e.g. "t1:key". The namespace must not be empty nor contain the separator, so resetting "t1" leaves "t10" untouched.
+func NewNamespaced(s storage.IStorage, namespace string) (*Namespaced, error) {
+	if namespace == "" || strings.Contains(namespace, NamespaceSeparator) {
+		return nil, ErrNamespaceInvalid
+	}
+
+	return &Namespaced{
+		storage: s,
+		prefix:  namespace + NamespaceSeparator,
+	}, nil
+}
+
+// Get gets the value for the given key.
+func (n *Namespaced) Get(key string) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+
+	return n.storage.Get(n.prefix + key)
+}
+
+// Set stores the given value for the given key along with an expiration value, 0 means no expiration.
+func (n *Namespaced) Set(key string, val []byte, exp time.Duration) error {
+	if len(key) == 0 {
+		return nil
+	}
+
+	return n.storage.Set(n.prefix+key, val, exp)
+}
+
+// Delete deletes the value for the given key.
+func (n *Namespaced) Delete(key string) error {
+	if len(key) == 0 {
+		return nil
+	}
+
+	return n.storage.Delete(n.prefix + key)
+}
+
+// Reset deletes only the keys of the namespace, leaving the other tenants untouched.
+// It requires a backend implementing storage.IScanStorage.
+func (n *Namespaced) Reset() error {
+	scanner, ok := storage.Scanner(n.storage)
+	if !ok {
+		return ErrResetUnsupported
+	}
+
+	keys, err := scanner.Keys(n.prefix)
+	if err != nil {
+		return err
+	}
+
+	return storage.Batch(n.storage).DeleteMany(keys)
+}
+
+// Close does nothing, the shared backend is closed by its owner.
+func (n *Namespaced) Close() error {
+	return nil
+}
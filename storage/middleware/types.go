@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"crypto/cipher"
+	"sync/atomic"
+	"time"
+
+	"github.com/leliuga/data/storage"
+)
+
+type (
+	// Namespaced defines a storage that prefixes every key with its namespace, so several tenants can share one backend.
+	// It implements storage.IStorage.
+	Namespaced struct {
+		storage storage.IStorage
+		prefix  string
+	}
+
+	// Encrypted defines a storage that encrypts every value with AES-GCM. It implements storage.IStorage.
+	Encrypted struct {
+		storage storage.IStorage
+		aead    cipher.AEAD
+	}
+
+	// Compressed defines a storage that compresses every value with DEFLATE. It implements storage.IStorage.
+	Compressed struct {
+		storage storage.IStorage
+		level   int
+		minSize int
+	}
+
+	// Instrumented defines a storage that counts operations, hits, misses and latency. It implements storage.IStorage.
+	Instrumented struct {
+		storage   storage.IStorage
+		observers []Observer
+		hits      atomic.Int64
+		misses    atomic.Int64
+		sets      atomic.Int64
+		deletes   atomic.Int64
+		resets    atomic.Int64
+		errors    atomic.Int64
+		latency   [operationCount]atomic.Int64
+	}
+
+	// Stats defines a snapshot of the Instrumented counters.
+	Stats struct {
+		Hits    int64                       `json:"hits"    yaml:"Hits"`
+		Misses  int64                       `json:"misses"  yaml:"Misses"`
+		Sets    int64                       `json:"sets"    yaml:"Sets"`
+		Deletes int64                       `json:"deletes" yaml:"Deletes"`
+		Resets  int64                       `json:"resets"  yaml:"Resets"`
+		Errors  int64                       `json:"errors"  yaml:"Errors"`
+		Latency map[Operation]time.Duration `json:"latency" yaml:"Latency"`
+	}
+
+	// Observer is called by Instrumented after every operation, e.g. to log it.
+	Observer func(op Operation, key string, duration time.Duration, err error)
+
+	// Operation defines a storage operation.
+	Operation uint8
+)
@@ -0,0 +1,257 @@
+package storagetest
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leliuga/data/storage"
+)
+
+const (
+	// Expiry is the expiration used by the suite when checking that expired keys are no longer returned.
+	Expiry = 100 * time.Millisecond
+
+	// Concurrency is the number of goroutines used by the suite when checking concurrent access.
+	Concurrency = 16
+)
+
+// RunSuite runs the storage.IStorage conformance suite. The factory is called once per sub-test and must return a new,
+// empty storage, the suite closes it when the sub-test finishes.
+func RunSuite(t *testing.T, factory func(t *testing.T) storage.IStorage) {
+	t.Helper()
+
+	for _, tc := range []struct {
+		name   string
+		fn     func(*testing.T, storage.IStorage)
+		closes bool
+	}{
+		{"GetMissing", testGetMissing, false},
+		{"SetGet", testSetGet, false},
+		{"Overwrite", testOverwrite, false},
+		{"EmptyKey", testEmptyKey, false},
+		{"EmptyValue", testEmptyValue, false},
+		{"NoExpiration", testNoExpiration, false},
+		{"Expiration", testExpiration, false},
+		{"Delete", testDelete, false},
+		{"DeleteMissing", testDeleteMissing, false},
+		{"Reset", testReset, false},
+		{"Concurrency", testConcurrency, false},
+		{"Close", testClose, true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s := factory(t)
+			if !tc.closes {
+				t.Cleanup(func() {
+					if err := s.Close(); err != nil {
+						t.Errorf("Close() error = %v", err)
+					}
+				})
+			}
+
+			tc.fn(t, s)
+		})
+	}
+}
+
+// testGetMissing checks that a missing key returns `nil, nil`.
+func testGetMissing(t *testing.T, s storage.IStorage) {
+	mustNotExist(t, s, "missing")
+}
+
+// testSetGet checks that a stored value is returned unchanged.
+func testSetGet(t *testing.T, s storage.IStorage) {
+	mustSet(t, s, "key", []byte("value"), 0)
+	mustGet(t, s, "key", []byte("value"))
+}
+
+// testOverwrite checks that a later Set replaces the stored value.
+func testOverwrite(t *testing.T, s storage.IStorage) {
+	mustSet(t, s, "key", []byte("first"), 0)
+	mustSet(t, s, "key", []byte("second"), 0)
+	mustGet(t, s, "key", []byte("second"))
+}
+
+// testEmptyKey checks that an empty key is ignored without an error.
+func testEmptyKey(t *testing.T, s storage.IStorage) {
+	mustSet(t, s, "", []byte("value"), 0)
+	mustNotExist(t, s, "")
+}
+
+// testEmptyValue checks that an empty value is ignored without an error and does not replace the stored value.
+func testEmptyValue(t *testing.T, s storage.IStorage) {
+	mustSet(t, s, "empty", nil, 0)
+	mustSet(t, s, "empty", []byte{}, 0)
+	mustNotExist(t, s, "empty")
+
+	mustSet(t, s, "key", []byte("value"), 0)
+	mustSet(t, s, "key", nil, 0)
+	mustGet(t, s, "key", []byte("value"))
+}
+
+// testNoExpiration checks that a zero expiration keeps the key.
+func testNoExpiration(t *testing.T, s storage.IStorage) {
+	mustSet(t, s, "key", []byte("value"), 0)
+	time.Sleep(2 * Expiry)
+	mustGet(t, s, "key", []byte("value"))
+}
+
+// testExpiration checks that a key is returned before its expiration and is missing after it.
+func testExpiration(t *testing.T, s storage.IStorage) {
+	mustSet(t, s, "key", []byte("value"), Expiry)
+	mustSet(t, s, "other", []byte("value"), 0)
+	mustGet(t, s, "key", []byte("value"))
+
+	time.Sleep(2 * Expiry)
+	mustNotExist(t, s, "key")
+	mustGet(t, s, "other", []byte("value"))
+}
+
+// testDelete checks that a deleted key is missing.
+func testDelete(t *testing.T, s storage.IStorage) {
+	mustSet(t, s, "key", []byte("value"), 0)
+	mustSet(t, s, "other", []byte("value"), 0)
+	mustDelete(t, s, "key")
+	mustNotExist(t, s, "key")
+	mustGet(t, s, "other", []byte("value"))
+}
+
+// testDeleteMissing checks that deleting a missing key is not an error.
+func testDeleteMissing(t *testing.T, s storage.IStorage) {
+	mustDelete(t, s, "missing")
+	mustDelete(t, s, "")
+}
+
+// testReset checks that Reset deletes all keys and the storage stays usable.
+func testReset(t *testing.T, s storage.IStorage) {
+	for i := 0; i < 10; i++ {
+		mustSet(t, s, fmt.Sprintf("key-%d", i), []byte("value"), 0)
+	}
+
+	if err := s.Reset(); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		mustNotExist(t, s, fmt.Sprintf("key-%d", i))
+	}
+
+	mustSet(t, s, "key", []byte("value"), 0)
+	mustGet(t, s, "key", []byte("value"))
+}
+
+// testConcurrency checks that concurrent operations on own and shared keys are safe and consistent.
+func testConcurrency(t *testing.T, s storage.IStorage) {
+	var wg sync.WaitGroup
+
+	errs := make(chan error, Concurrency)
+	for i := 0; i < Concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("key-%d", i)
+			for j := 0; j < 100; j++ {
+				value := []byte(fmt.Sprintf("value-%d-%d", i, j))
+				if err := s.Set(key, value, 0); err != nil {
+					errs <- err
+					return
+				}
+
+				got, err := s.Get(key)
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				if !bytes.Equal(got, value) {
+					errs <- fmt.Errorf("Get(%q) = %q, want %q", key, got, value)
+					return
+				}
+
+				if err = s.Set("shared", value, 0); err != nil {
+					errs <- err
+					return
+				}
+
+				if _, err = s.Get("shared"); err != nil {
+					errs <- err
+					return
+				}
+			}
+
+			if err := s.Delete(key); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	for i := 0; i < Concurrency; i++ {
+		mustNotExist(t, s, fmt.Sprintf("key-%d", i))
+	}
+}
+
+// testClose checks that Close returns no error.
+func testClose(t *testing.T, s storage.IStorage) {
+	mustSet(t, s, "key", []byte("value"), Expiry)
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+// mustSet stores the value or fails the test.
+func mustSet(t *testing.T, s storage.IStorage, key string, value []byte, exp time.Duration) {
+	t.Helper()
+
+	if err := s.Set(key, value, exp); err != nil {
+		t.Fatalf("Set(%q) error = %v", key, err)
+	}
+}
+
+// mustGet checks that the key holds the expected value or fails the test.
+func mustGet(t *testing.T, s storage.IStorage, key string, want []byte) {
+	t.Helper()
+
+	got, err := s.Get(key)
+	if err != nil {
+		t.Fatalf("Get(%q) error = %v", key, err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Fatalf("Get(%q) = %q, want %q", key, got, want)
+	}
+}
+
+// mustNotExist checks that the key returns `nil, nil` or fails the test.
+func mustNotExist(t *testing.T, s storage.IStorage, key string) {
+	t.Helper()
+
+	got, err := s.Get(key)
+	if err != nil {
+		t.Fatalf("Get(%q) error = %v", key, err)
+	}
+
+	if got != nil {
+		t.Fatalf("Get(%q) = %q, want nil", key, got)
+	}
+}
+
+// mustDelete deletes the key or fails the test.
+func mustDelete(t *testing.T, s storage.IStorage, key string) {
+	t.Helper()
+
+	if err := s.Delete(key); err != nil {
+		t.Fatalf("Delete(%q) error = %v", key, err)
+	}
+}
@@ -0,0 +1,75 @@
+package tiered
+
+import (
+	"bytes"
+	"errors"
+)
+
+const (
+	WriteInvalid WritePolicy = iota //
+	WriteThrough                    // Writes both tiers before returning
+	WriteBehind                     // Writes L1 before returning and L2 in the background, in order
+)
+
+var (
+	// WritePolicyNames is a map of write policy values to write policy names.
+	WritePolicyNames = map[WritePolicy]string{
+		WriteThrough: "through",
+		WriteBehind:  "behind",
+	}
+
+	// DefaultConfig is the default two-tier storage configuration.
+	DefaultConfig = Config{
+		Write:     WriteThrough,
+		QueueSize: 1024,
+	}
+
+	// ErrWritePolicyInvalid is returned when the write policy is invalid.
+	ErrWritePolicyInvalid = errors.New("invalid write policy")
+)
+
+// String write policy to string
+func (p WritePolicy) String() string {
+	return WritePolicyNames[p]
+}
+
+// MarshalJSON write policy to json
+func (p WritePolicy) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + p.String() + `"`), nil
+}
+
+// UnmarshalJSON write policy from json
+func (p *WritePolicy) UnmarshalJSON(b []byte) error {
+	*p = ParseWritePolicy(string(bytes.Trim(b, `"`)))
+
+	return nil
+}
+
+// ParseWritePolicy parses write policy string.
+func ParseWritePolicy(name string) WritePolicy {
+	for k, v := range WritePolicyNames {
+		if v == name {
+			return k
+		}
+	}
+
+	return WriteInvalid
+}
+
+// configDefault returns the first provided config with the unset values filled by DefaultConfig.
+func configDefault(config ...Config) Config {
+	if len(config) < 1 {
+		return DefaultConfig
+	}
+
+	cfg := config[0]
+	if cfg.Write == WriteInvalid {
+		cfg.Write = DefaultConfig.Write
+	}
+
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultConfig.QueueSize
+	}
+
+	return cfg
+}
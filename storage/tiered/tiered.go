@@ -0,0 +1,363 @@
+package tiered
+
+import (
+	"errors"
+	"time"
+
+	"github.com/leliuga/data/storage"
+)
+
+var (
+	// ErrClosed is returned when the storage is used after it has been closed.
+	ErrClosed = errors.New("storage is closed")
+)
+
+// New creates a new two-tier storage reading through l1 then l2. Both tiers store the values as is, so l2 may be
+// an existing storage already holding values. The expiration of a value of l2 is not known, so a value back-filled
+// from l2 into l1 expires after the L1Expiration config, or never when it is 0.
+func New(l1, l2 storage.IStorage, config ...Config) (*Storage, error) {
+	cfg := configDefault(config...)
+	if _, ok := WritePolicyNames[cfg.Write]; !ok {
+		return nil, ErrWritePolicyInvalid
+	}
+
+	s := &Storage{
+		config:   cfg,
+		l1:       l1,
+		l1Atomic: storage.Atomic(l1),
+		l2:       l2,
+		loads:    make(map[string]*load),
+	}
+
+	if cfg.Write == WriteBehind {
+		s.queue = make(chan write, cfg.QueueSize)
+		s.pending = make(map[string]*pending)
+		s.wg.Add(1)
+		go s.run()
+	}
+
+	return s, nil
+}
+
+// Get gets the value for the given key from L1, or from L2 on a miss, back-filling L1.
+// Concurrent misses for the same key share a single L2 read.
+// `nil, nil` is returned when the key does not exist or is expired.
+func (s *Storage) Get(key string) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	// a failing L1 is only a cache miss
+	if value, err := s.l1.Get(key); err == nil && value != nil {
+		return value, nil
+	}
+
+	// a write behind not yet in L2 is the latest value
+	if p, ok := s.queued(key); ok {
+		if p.value != nil && !expired(p.expireAt, time.Now().UnixNano()) {
+			return append([]byte(nil), p.value...), nil
+		}
+
+		return nil, nil
+	}
+
+	v, err, shared := s.group.Do(key, func() (any, error) {
+		return s.load(key)
+	})
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	value := v.([]byte)
+	if shared {
+		value = append([]byte(nil), value...)
+	}
+
+	return value, nil
+}
+
+// Set stores the given value for the given key in both tiers along with an expiration value, 0 means no expiration.
+// Empty key or value will be ignored without an error.
+func (s *Storage) Set(key string, val []byte, exp time.Duration) error {
+	if len(key) == 0 || len(val) == 0 {
+		return nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	s.invalidate(key)
+
+	if s.config.Write == WriteBehind {
+		var expireAt int64
+		if exp > 0 {
+			expireAt = time.Now().Add(exp).UnixNano()
+		}
+
+		if err := s.l1.Set(key, val, s.l1Expiration(exp)); err != nil {
+			return err
+		}
+
+		s.enqueue(write{key: key, value: append([]byte(nil), val...), expireAt: expireAt})
+
+		return nil
+	}
+
+	// L2 first, so L1 never holds a value L2 does not have
+	if err := s.l2.Set(key, val, exp); err != nil {
+		return err
+	}
+
+	return s.l1.Set(key, val, s.l1Expiration(exp))
+}
+
+// Delete deletes the value for the given key from both tiers.
+// It returns no error if the storage does not contain the key.
+func (s *Storage) Delete(key string) error {
+	if len(key) == 0 {
+		return nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	s.invalidate(key)
+
+	if s.config.Write == WriteBehind {
+		if err := s.l1.Delete(key); err != nil {
+			return err
+		}
+
+		s.enqueue(write{key: key})
+
+		return nil
+	}
+
+	if err := s.l2.Delete(key); err != nil {
+		return err
+	}
+
+	return s.l1.Delete(key)
+}
+
+// Reset waits for the pending writes and resets both tiers.
+func (s *Storage) Reset() error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	s.flush()
+
+	if err := s.l2.Reset(); err != nil {
+		return err
+	}
+
+	return s.l1.Reset()
+}
+
+// Flush waits until the pending writes have reached L2.
+func (s *Storage) Flush() error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	s.flush()
+
+	return nil
+}
+
+// Close waits for the pending writes and closes both tiers.
+func (s *Storage) Close() error {
+	var err error
+
+	s.once.Do(func() {
+		s.mutex.Lock()
+		s.closed = true
+		if s.queue != nil {
+			close(s.queue)
+		}
+		s.mutex.Unlock()
+
+		s.wg.Wait()
+
+		err = s.l1.Close()
+		if err2 := s.l2.Close(); err == nil {
+			err = err2
+		}
+	})
+
+	return err
+}
+
+// load reads the value for the given key from L2 and back-fills L1 unless the key has been written meanwhile.
+func (s *Storage) load(key string) ([]byte, error) {
+	s.loader.Lock()
+	l, ok := s.loads[key]
+	if !ok {
+		l = &load{}
+		s.loads[key] = l
+	}
+	l.count++
+	generation := l.generation
+	s.loader.Unlock()
+
+	defer func() {
+		s.loader.Lock()
+		if l.count--; l.count == 0 {
+			delete(s.loads, key)
+		}
+		s.loader.Unlock()
+	}()
+
+	value, err := s.l2.Get(key)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	// the back-fill holds the loader lock, so a write either bumps the generation before it or overwrites it after
+	s.loader.Lock()
+	defer s.loader.Unlock()
+
+	if l.generation != generation {
+		return value, nil
+	}
+
+	if _, err = s.l1Atomic.SetNX(key, value, s.config.L1Expiration); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// invalidate bumps the generation of the loads of the key in progress, so they do not back-fill L1, and forgets
+// their shared result, so a later Get reads the key again. It must be called before writing the tiers.
+func (s *Storage) invalidate(key string) {
+	s.loader.Lock()
+	if l, ok := s.loads[key]; ok {
+		l.generation++
+	}
+	s.loader.Unlock()
+
+	s.group.Forget(key)
+}
+
+// run writes the pending writes to L2 in order until the queue is closed.
+func (s *Storage) run() {
+	defer s.wg.Done()
+
+	for w := range s.queue {
+		if w.done != nil {
+			close(w.done)
+			continue
+		}
+
+		var exp time.Duration
+		if w.expireAt != 0 {
+			exp = time.Until(time.Unix(0, w.expireAt))
+		}
+
+		var err error
+		if w.value == nil || (w.expireAt != 0 && exp <= 0) {
+			err = s.l2.Delete(w.key)
+		} else {
+			err = s.l2.Set(w.key, w.value, exp)
+		}
+
+		s.dequeue(w.key)
+
+		if err != nil && s.config.OnError != nil {
+			s.config.OnError(w.key, err)
+		}
+	}
+}
+
+// enqueue queues a write behind and records it as the latest value of the key. The caller must hold the read lock.
+func (s *Storage) enqueue(w write) {
+	s.locker.Lock()
+	p, ok := s.pending[w.key]
+	if !ok {
+		p = &pending{}
+		s.pending[w.key] = p
+	}
+
+	p.value, p.expireAt = w.value, w.expireAt
+	p.count++
+	s.locker.Unlock()
+
+	s.queue <- w
+}
+
+// dequeue forgets a write behind once it has reached L2.
+func (s *Storage) dequeue(key string) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	if p, ok := s.pending[key]; ok {
+		if p.count--; p.count == 0 {
+			delete(s.pending, key)
+		}
+	}
+}
+
+// queued returns the latest write queued for the key, with a nil value for a delete, and whether a write is queued.
+func (s *Storage) queued(key string) (pending, bool) {
+	if s.pending == nil {
+		return pending{}, false
+	}
+
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	p, ok := s.pending[key]
+	if !ok {
+		return pending{}, false
+	}
+
+	return *p, true
+}
+
+// flush waits until the writes queued before the call have reached L2. The caller must hold the read lock.
+func (s *Storage) flush() {
+	if s.queue == nil {
+		return
+	}
+
+	done := make(chan struct{})
+	s.queue <- write{done: done}
+	<-done
+}
+
+// l1Expiration returns the L1 expiration for the given expiration, capped by the L1Expiration config.
+func (s *Storage) l1Expiration(exp time.Duration) time.Duration {
+	if s.config.L1Expiration > 0 && (exp == 0 || exp > s.config.L1Expiration) {
+		return s.config.L1Expiration
+	}
+
+	return exp
+}
+
+// expired returns whether the expiration time is reached at the given time in unix nanoseconds.
+func expired(expireAt, now int64) bool {
+	return expireAt != 0 && expireAt <= now
+}
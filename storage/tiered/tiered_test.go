@@ -0,0 +1,128 @@
+package tiered_test
+
+import (
+	"testing"
+
+	"github.com/leliuga/data/storage"
+	"github.com/leliuga/data/storage/bounded"
+	"github.com/leliuga/data/storage/memory"
+	"github.com/leliuga/data/storage/storagetest"
+	"github.com/leliuga/data/storage/tiered"
+)
+
+func TestSuite(t *testing.T) {
+	for _, write := range []tiered.WritePolicy{tiered.WriteThrough, tiered.WriteBehind} {
+		write := write
+		t.Run(write.String(), func(t *testing.T) {
+			storagetest.RunSuite(t, func(t *testing.T) storage.IStorage {
+				s, err := tiered.New(memory.New(), memory.New(), tiered.Config{Write: write})
+				if err != nil {
+					t.Fatalf("New() error = %v", err)
+				}
+
+				return s
+			})
+		})
+	}
+}
+
+// blockingStorage blocks the Get calls after reading the wrapped storage until release is closed.
+type blockingStorage struct {
+	storage.IStorage
+	read    chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingStorage) Get(key string) ([]byte, error) {
+	value, err := b.IStorage.Get(key)
+	if b.release != nil {
+		close(b.read)
+		<-b.release
+	}
+
+	return value, err
+}
+
+func TestDeleteDuringLoad(t *testing.T) {
+	l1, err := bounded.New(bounded.Config{MaxEntries: 16})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	l2 := &blockingStorage{IStorage: memory.New()}
+	s, err := tiered.New(l1, l2)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer s.Close()
+
+	if err = s.Set("key", []byte("old"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err = l1.Delete("key"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	l2.read, l2.release = make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = s.Get("key")
+	}()
+
+	<-l2.read
+	if err = s.Delete("key"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	l2.release <- struct{}{}
+	<-done
+	l2.release = nil
+
+	if got, _ := l1.Get("key"); got != nil {
+		t.Errorf("L1 Get() = %q, want nil", got)
+	}
+	if got, _ := s.Get("key"); got != nil {
+		t.Errorf("Get() = %q, want nil", got)
+	}
+}
+
+func TestExistingL2(t *testing.T) {
+	values := map[string]string{
+		"short": "hi",
+		"long":  "hello world, pre-existing",
+	}
+
+	l1, l2 := memory.New(), memory.New()
+	for k, v := range values {
+		if err := l2.Set(k, []byte(v), 0); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	s, err := tiered.New(l1, l2)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer s.Close()
+
+	for k, v := range values {
+		got, err := s.Get(k)
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v", k, err)
+		}
+		if string(got) != v {
+			t.Errorf("Get(%q) = %q, want %q", k, got, v)
+		}
+
+		if got, _ = l1.Get(k); string(got) != v {
+			t.Errorf("L1 Get(%q) = %q, want %q", k, got, v)
+		}
+	}
+
+	if err = s.Set("written", []byte("value"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, _ := l2.Get("written"); string(got) != "value" {
+		t.Errorf("L2 Get() = %q, want %q", got, "value")
+	}
+}
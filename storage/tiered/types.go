@@ -0,0 +1,74 @@
+package tiered
+
+import (
+	"sync"
+	"time"
+
+	"github.com/leliuga/data/storage"
+	"golang.org/x/sync/singleflight"
+)
+
+type (
+	// Storage defines a two-tier storage reading through a fast L1 in front of a slow L2. It implements storage.IStorage.
+	Storage struct {
+		config   Config
+		l1       storage.IStorage
+		l1Atomic storage.IAtomicStorage
+		l2       storage.IStorage
+		group    singleflight.Group
+		queue    chan write
+		pending  map[string]*pending
+		loads    map[string]*load
+		wg       sync.WaitGroup
+		once     sync.Once
+		mutex    sync.RWMutex
+		locker   sync.Mutex
+		loader   sync.Mutex
+		closed   bool
+	}
+
+	// Config defines the two-tier storage configuration.
+	Config struct {
+		// Write is the policy used to write to L2.
+		// Optional. Default is WriteThrough.
+		Write WritePolicy `json:"write" yaml:"Write"`
+
+		// QueueSize is the number of pending L2 writes buffered when Write is WriteBehind.
+		// Optional. Default is 1024.
+		QueueSize int `json:"queue_size" yaml:"QueueSize"`
+
+		// L1Expiration caps the expiration of the values kept in L1, 0 means the expiration of the written value. It is
+		// the expiration of the values back-filled from L2, whose expiration is not known, 0 meaning no expiration.
+		// Optional. Default is 0.
+		L1Expiration time.Duration `json:"l1_expiration" yaml:"L1Expiration"`
+
+		// OnError is called when a write behind to L2 fails.
+		// Optional. Default is nil.
+		OnError func(key string, err error) `json:"-"`
+	}
+
+	// WritePolicy defines how writes reach L2.
+	WritePolicy uint8
+
+	// write defines a pending L2 write when Write is WriteBehind.
+	write struct {
+		key      string
+		value    []byte
+		expireAt int64 // unix nanoseconds, 0 means no expiration
+		done     chan struct{}
+	}
+
+	// load defines the generation of a key being loaded from L2, bumped by every write so a load started before
+	// the write does not back-fill L1, and the number of loads in progress.
+	load struct {
+		generation uint64
+		count      int
+	}
+
+	// pending defines the latest value queued for a key, its expiration time and the number of its queued writes.
+	pending struct {
+		value    []byte
+		expireAt int64 // unix nanoseconds, 0 means no expiration
+		count    int
+	}
+)
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/leliuga/data/contenttype"
+)
+
+// NewTyped creates a new typed storage that encodes values with the given content type codec,
+// e.g. contenttype.Set[contenttype.Json].
+func NewTyped[T any](storage IStorage, codec contenttype.IContentType) *Typed[T] {
+	return &Typed[T]{
+		storage: storage,
+		codec:   codec,
+	}
+}
+
+// Get gets and decodes the value for the given key.
+// The zero value and false are returned when the key does not exist.
+func (t *Typed[T]) Get(key string) (T, bool, error) {
+	var value T
+
+	b, err := t.storage.Get(key)
+	if err != nil || b == nil {
+		return value, false, err
+	}
+
+	if err = t.codec.Unmarshal(bytes.NewReader(b), &value); err != nil {
+		return value, false, err
+	}
+
+	return value, true, nil
+}
+
+// Set encodes and stores the given value for the given key along with an expiration value, 0 means no expiration.
+func (t *Typed[T]) Set(key string, value T, exp time.Duration) error {
+	buffer := bytes.NewBuffer(nil)
+	if err := contenttype.Encode(t.codec, buffer, value); err != nil {
+		return err
+	}
+
+	return t.storage.Set(key, buffer.Bytes(), exp)
+}
+
+// Delete deletes the value for the given key.
+func (t *Typed[T]) Delete(key string) error {
+	return t.storage.Delete(key)
+}
+
+// Storage returns the wrapped storage.
+func (t *Typed[T]) Storage() IStorage {
+	return t.storage
+}
@@ -1,7 +1,11 @@
 package storage
 
 import (
+	"context"
+	"sync"
 	"time"
+
+	"github.com/leliuga/data/contenttype"
 )
 
 type (
@@ -25,4 +29,101 @@ type (
 		// Close closes the storage and will stop any running garbage collectors and open connections.
 		Close() error
 	}
+
+	// IBatchStorage interface for storages able to get, set and delete many keys at once.
+	IBatchStorage interface {
+		IStorage
+
+		// GetMany gets the values for the given keys.
+		// Keys that do not exist are omitted from the result.
+		GetMany(keys []string) (map[string][]byte, error)
+
+		// SetMany stores the given values along with an expiration value, 0 means no expiration.
+		// Empty keys or values will be ignored without an error.
+		SetMany(entries map[string][]byte, exp time.Duration) error
+
+		// DeleteMany deletes the values for the given keys.
+		// It returns no error if the storage does not contain some of the keys.
+		DeleteMany(keys []string) error
+	}
+
+	// IScanStorage interface for storages able to iterate over their keys.
+	IScanStorage interface {
+		IStorage
+
+		// Keys returns the sorted keys starting with the given prefix, an empty prefix matches all keys.
+		Keys(prefix string) ([]string, error)
+
+		// Scan calls fn for each key starting with the given prefix in sorted order, until fn returns false.
+		// The storage may be used from fn.
+		Scan(prefix string, fn func(key string, value []byte) bool) error
+	}
+
+	// IStorageContext interface for storages honouring the cancellation and the deadline of a context.
+	// It mirrors IStorage with the same semantics.
+	IStorageContext interface {
+		// GetContext gets the value for the given key.
+		// `nil, nil` is returned when the key does not exist
+		GetContext(ctx context.Context, key string) ([]byte, error)
+
+		// SetContext stores the given value for the given key along with an expiration value, 0 means no expiration.
+		// Empty key or value will be ignored without an error.
+		SetContext(ctx context.Context, key string, val []byte, exp time.Duration) error
+
+		// DeleteContext deletes the value for the given key.
+		// It returns no error if the storage does not contain the key,
+		DeleteContext(ctx context.Context, key string) error
+
+		// ResetContext resets the storage and delete all keys.
+		ResetContext(ctx context.Context) error
+
+		// Close closes the storage and will stop any running garbage collectors and open connections.
+		Close() error
+	}
+
+	// IAtomicStorage interface for storages able to update a key atomically.
+	IAtomicStorage interface {
+		IStorage
+
+		// SetNX stores the given value for the given key only when the key does not exist, and reports whether it was
+		// stored. Empty key or value will be ignored without an error.
+		SetNX(key string, val []byte, exp time.Duration) (bool, error)
+
+		// CompareAndSwap stores the new value for the given key only when the current value equals the old one, and
+		// reports whether it was stored. A nil old value matches a key that does not exist, an empty new value
+		// deletes the key.
+		CompareAndSwap(key string, old, new []byte, exp time.Duration) (bool, error)
+
+		// Increment adds delta to the integer stored for the given key and returns the new value. A key that does not
+		// exist counts from 0 and is created with the given expiration, an existing key keeps its expiration.
+		Increment(key string, delta int64, exp time.Duration) (int64, error)
+	}
+
+	// atomicStorage defines an IAtomicStorage emulated on top of a plain IStorage with striped locks.
+	atomicStorage struct {
+		IStorage
+		stripes [64]sync.Mutex
+	}
+
+	// contextStorage defines an IStorageContext on top of a plain IStorage.
+	contextStorage struct {
+		storage IStorage
+	}
+
+	// plainStorage defines an IStorage on top of an IStorageContext.
+	plainStorage struct {
+		storage IStorageContext
+		timeout time.Duration
+	}
+
+	// batchStorage defines an IBatchStorage emulated on top of a plain IStorage.
+	batchStorage struct {
+		IStorage
+	}
+
+	// Typed defines a storage of T values encoded by a content type. It wraps any IStorage.
+	Typed[T any] struct {
+		storage IStorage
+		codec   contenttype.IContentType
+	}
 )